@@ -49,19 +49,69 @@ func NextRuntime(loc *time.Location, r int) time.Time {
 	return time.Date(year, month, day, r, 0, 0, 0, loc)
 }
 
+// NextWindow returns the part of the next occurrence of the daily window
+// from startHour to endHour in loc that lies between now and before, e.g.
+// the next run of the scheduler. Only occurrences on a weekday in loc count,
+// and it returns false if none of them starts in time
+func NextWindow(now, before time.Time, startHour, endHour int, loc *time.Location) (time.Time, time.Time, bool) {
+	now = now.In(loc)
+	for day := now; ; day = day.AddDate(0, 0, 1) {
+		year, month, date := day.Date()
+		start := time.Date(year, month, date, startHour, 0, 0, 0, loc)
+		end := time.Date(year, month, date, endHour, 0, 0, 0, loc)
+		if !start.Before(before) {
+			return time.Time{}, time.Time{}, false
+		}
+		if !isWeekday(start) || !end.After(now) {
+			continue
+		}
+
+		if start.Before(now) {
+			start = now
+		}
+		if end.After(before) {
+			end = before
+		}
+		return start, end, true
+	}
+}
+
 // RandomTimeInRange returns a random time within the range specified by startHour and endHour
 func RandomTimeInRange(startHour int, endHour int, loc *time.Location) time.Time {
+	return RandomTimesInRange(startHour, endHour, loc, 1)[0]
+}
+
+// RandomTimesInRange returns n random times within the range specified by
+// startHour and endHour today, in order. The range is split into n equal
+// slots with one time in each, so that the times are spread over the range
+func RandomTimesInRange(startHour int, endHour int, loc *time.Location, n int) []time.Time {
+	year, month, date := time.Now().In(loc).Date()
+	rangeStart := time.Date(year, month, date, startHour, 0, 0, 0, loc)
+	rangeEnd := time.Date(year, month, date, endHour, 0, 0, 0, loc)
+	return RandomTimesBetween(rangeStart, rangeEnd, n)
+}
+
+// RandomTimesBetween returns up to n random times in [start, end), in
+// order, with one time in each of n equal slots of the range
+// It returns no times if the range is shorter than a minute
+func RandomTimesBetween(start time.Time, end time.Time, n int) []time.Time {
 	// calculate the number of minutes in the range
-	minutesInRange := (endHour - startHour) * 60
+	minutesInRange := int(end.Sub(start) / time.Minute)
+	if n > minutesInRange {
+		n = minutesInRange
+	}
+	if n <= 0 {
+		return nil
+	}
 
-	// calculate a random minute-offset in range [0, minutesInRange)
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	randMinuteOffset := r.Intn(minutesInRange)
-	offsetDuration := time.Duration(randMinuteOffset) * time.Minute
-
-	// Add the minute offset to the start of the range to get a random
-	// time within the range
-	year, month, date := time.Now().Date()
-	rangeStart := time.Date(year, month, date, startHour, 0, 0, 0, loc)
-	return rangeStart.Add(offsetDuration)
+	times := make([]time.Time, n)
+	for i := range times {
+		// calculate a random minute-offset in the slot [slotStart, slotEnd)
+		slotStart := i * minutesInRange / n
+		slotEnd := (i + 1) * minutesInRange / n
+		randMinuteOffset := slotStart + r.Intn(slotEnd-slotStart)
+		times[i] = start.Add(time.Duration(randMinuteOffset) * time.Minute)
+	}
+	return times
 }
@@ -20,4 +20,54 @@ func TestIsWeekDay(t *testing.T) {
 	assert.False(t, isWeekday(monday.Add(time.Hour*24*6)))
 }
 
+func TestRandomTimesInRange(t *testing.T) {
+	times := RandomTimesInRange(10, 16, time.UTC, 4)
+	if assert.Len(t, times, 4) {
+		for i, killtime := range times {
+			// Each time falls into its own 90 minute slot of the range
+			slotStart := time.Date(killtime.Year(), killtime.Month(), killtime.Day(), 10, 0, 0, 0, time.UTC).Add(time.Duration(i*90) * time.Minute)
+			assert.False(t, killtime.Before(slotStart), "Expected time %d after %s, got %s", i, slotStart, killtime)
+			assert.True(t, killtime.Before(slotStart.Add(90*time.Minute)), "Expected time %d before the end of its slot, got %s", i, killtime)
+		}
+	}
+
+	assert.Len(t, RandomTimesInRange(10, 11, time.UTC, 100), 60, "Expected at most one time per minute")
+}
+
+func TestNextWindow(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	// Monday 17:00 in Berlin, the next run is on Tuesday at 17:00
+	now := time.Date(2018, 4, 16, 17, 0, 0, 0, berlin)
+	nextRun := now.AddDate(0, 0, 1)
+
+	start, end, ok := NextWindow(now, nextRun, 9, 17, berlin)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2018, 4, 17, 9, 0, 0, 0, berlin), start, "Expected the window of the next day")
+	assert.Equal(t, time.Date(2018, 4, 17, 17, 0, 0, 0, berlin), end)
+
+	start, end, ok = NextWindow(now, nextRun, 12, 20, berlin)
+	assert.True(t, ok)
+	assert.Equal(t, now, start, "Expected what is left of the window under way")
+	assert.Equal(t, time.Date(2018, 4, 16, 20, 0, 0, 0, berlin), end)
+
+	start, end, ok = NextWindow(now, nextRun, 9, 23, berlin)
+	assert.True(t, ok)
+	assert.Equal(t, now, start)
+	assert.Equal(t, time.Date(2018, 4, 16, 23, 0, 0, 0, berlin), end)
+
+	// Friday 17:00, the next run is on Monday at 17:00
+	friday := time.Date(2018, 4, 20, 17, 0, 0, 0, berlin)
+	start, end, ok = NextWindow(friday, friday.AddDate(0, 0, 3), 9, 17, berlin)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2018, 4, 23, 9, 0, 0, 0, berlin), start, "Expected the window to skip the weekend")
+	assert.Equal(t, time.Date(2018, 4, 23, 17, 0, 0, 0, berlin), end)
+
+	start, end, ok = NextWindow(now, nextRun.Add(-5*time.Hour), 9, 17, berlin)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2018, 4, 17, 12, 0, 0, 0, berlin), end, "Expected the window cut off at the next run")
+
+	_, _, ok = NextWindow(now, now.Add(12*time.Hour), 9, 17, berlin)
+	assert.False(t, ok, "Expected no window before the next run")
+}
+
 // FIXME:  add more tests
@@ -1,9 +1,11 @@
 package chaos
 
 import (
+	"context"
 	"fmt"
 	"time"
 
+	"github.com/golang/glog"
 	"github.com/pkg/errors"
 
 	"kube-monkey/internal/pkg/config"
@@ -11,8 +13,22 @@ import (
 	"kube-monkey/internal/pkg/victims"
 
 	kube "k8s.io/client-go/kubernetes"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
+// ErrCanceled is returned for a termination canceled before its kill time
+var ErrCanceled = errors.New("termination canceled")
+
+// How often the ready replicas of a victim are checked while it recovers
+var recoveryPollInterval = 5 * time.Second
+
+// Creates the executor used to signal containers, see config.KillTargetLabelKey
+var newPodExecutor = func() (victims.PodExecutor, error) {
+	return kubernetes.CreatePodExecutor()
+}
+
 type Chaos struct {
 	killAt time.Time
 	victim victims.Victim
@@ -36,9 +52,18 @@ func (c *Chaos) KillAt() time.Time {
 }
 
 // Schedule the execution of Chaos
-func (c *Chaos) Schedule(resultchan chan<- *Result) {
-	time.Sleep(c.DurationToKillTime())
-	c.Execute(resultchan)
+// If ctx is canceled before the kill time, the termination is not executed
+// and the result carries ErrCanceled along with the cause of the cancellation
+func (c *Chaos) Schedule(ctx context.Context, resultchan chan<- *Result) {
+	timer := time.NewTimer(c.DurationToKillTime())
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		c.Execute(resultchan)
+	case <-ctx.Done():
+		resultchan <- c.NewResult(fmt.Errorf("%w for %s %s: %v", ErrCanceled, c.Victim().Kind(), c.Victim().Name(), context.Cause(ctx)))
+	}
 }
 
 // DurationToKillTime calculates the duration from now until Chaos.killAt
@@ -56,20 +81,103 @@ func (c *Chaos) Execute(resultchan chan<- *Result) {
 		return
 	}
 
+	// Nodes are not namespaced, so only their enrollment is verified
+	if node, ok := c.Victim().(victims.NodeVictim); ok {
+		resultchan <- c.drainNode(clientset, node)
+		return
+	}
+
+	// Scenarios span several workloads and check their eligibility themselves
+	if scenario, ok := c.Victim().(victims.Scenario); ok {
+		resultchan <- c.runScenario(clientset, scenario)
+		return
+	}
+
 	err = c.verifyExecution(clientset)
 	if err != nil {
 		resultchan <- c.NewResult(err)
 		return
 	}
 
-	err = c.terminate(clientset)
+	// Remember how many replicas were ready, to watch the victim recover
+	reporter, verifyRecovery := c.Victim().(victims.VictimHealthReporter)
+	verifyRecovery = verifyRecovery && config.RecoveryTimeout() > 0 && !config.DryRun()
+	var readyBefore int32
+	if verifyRecovery {
+		readyBefore, _, err = reporter.Replicas(clientset)
+		if err != nil {
+			glog.Warningf("Failed to check replicas of %s %s before termination, not verifying its recovery. Error: %v", c.Victim().Kind(), c.Victim().Name(), err)
+			verifyRecovery = false
+		}
+	}
+
+	outcome, err := c.terminate(clientset)
 	if err != nil {
 		resultchan <- c.NewResult(err)
 		return
 	}
 
+	result := c.NewResult(nil)
+	result.addOutcome(outcome)
+
+	if verifyRecovery {
+		result.recoveryChecked = true
+		result.recoveryTime, result.recoveryErr = c.waitForRecovery(clientset, reporter, readyBefore, config.RecoveryTimeout())
+	}
+
+	// Victims that run to completion report how they finished
+	if watcher, ok := c.Victim().(victims.VictimCompletionWatcher); ok {
+		completion, err := watcher.WaitForCompletion(clientset, config.JobCompletionTimeout())
+		if err != nil {
+			glog.Warningf("Failed to check completion of %s %s. Error: %v", c.Victim().Kind(), c.Victim().Name(), err)
+		}
+		result.addOutcome(completion)
+	}
+
 	// Send a success msg
-	resultchan <- c.NewResult(nil)
+	resultchan <- result
+}
+
+// Run the scenario and report the workloads it affected
+func (c *Chaos) runScenario(clientset kube.Interface, scenario victims.Scenario) *Result {
+	outcome, affected, err := scenario.Run(clientset)
+	result := c.NewResult(err)
+	result.addOutcome(outcome)
+	result.affected = affected
+	return result
+}
+
+// Drain the node if it is still enrolled, and report the workloads whose
+// pods were evicted
+func (c *Chaos) drainNode(clientset kube.Interface, node victims.NodeVictim) *Result {
+	enrolled, err := node.IsEnrolled(clientset)
+	if err != nil {
+		return c.NewResult(err)
+	}
+	if !enrolled {
+		return c.NewResult(fmt.Errorf("%s %s is no longer enrolled in kube-monkey. Skipping", node.Kind(), node.Name()))
+	}
+
+	outcome, affected, err := node.Drain(clientset)
+	result := c.NewResult(err)
+	result.addOutcome(outcome)
+	result.affected = affected
+	return result
+}
+
+// Terminate killNum pods of the victim, or signal a container in each of
+// them if the victim targets a container
+func (c *Chaos) killPods(clientset kube.Interface, killNum int) (string, error) {
+	killer, ok := c.Victim().(victims.VictimContainerKiller)
+	if !ok || killer.KillTarget() == "" {
+		return "", c.Victim().DeleteRandomPods(clientset, killNum)
+	}
+
+	executor, err := newPodExecutor()
+	if err != nil {
+		return "", err
+	}
+	return killer.KillContainers(clientset, executor, killNum)
 }
 
 // Verify if the victim has opted out since scheduling
@@ -85,57 +193,141 @@ func (c *Chaos) verifyExecution(clientset kube.Interface) error {
 	}
 
 	// Has the victim been blacklisted since scheduling?
-	if c.Victim().IsBlacklisted() {
+	if c.Victim().IsBlacklisted(clientset) {
 		return fmt.Errorf("%s %s is blacklisted. Skipping", c.Victim().Kind(), c.Victim().Name())
 	}
 
 	// Has the victim been removed from the whitelist since scheduling?
-	if !c.Victim().IsWhitelisted() {
+	if !c.Victim().IsWhitelisted(clientset) {
 		return fmt.Errorf("%s %s is not whitelisted. Skipping", c.Victim().Kind(), c.Victim().Name())
 	}
 
+	// Is the victim healthy enough to lose pods?
+	if reporter, ok := c.Victim().(victims.VictimHealthReporter); ok {
+		return c.verifyHealth(clientset, reporter)
+	}
+
 	// Send back valid for termination
 	return nil
 }
 
+// Verify that enough replicas of the victim are ready for a termination
+func (c *Chaos) verifyHealth(clientset kube.Interface, reporter victims.VictimHealthReporter) error {
+	minHealthy, err := reporter.MinHealthyReplicas()
+	if err != nil || minHealthy == nil {
+		return err
+	}
+
+	ready, desired, err := reporter.Replicas(clientset)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to check replicas of %s %s", c.Victim().Kind(), c.Victim().Name())
+	}
+
+	required, err := intstr.GetScaledValueFromIntOrPercent(minHealthy, int(desired), true)
+	if err != nil {
+		return err
+	}
+
+	if int(ready) < required {
+		return fmt.Errorf("%s %s is not healthy: %d of %d replicas ready, but %s required. Skipping", c.Victim().Kind(), c.Victim().Name(), ready, desired, minHealthy.String())
+	}
+	return nil
+}
+
+// Watch the victim until at least readyBefore replicas are ready again,
+// and return how long that took after the termination
+func (c *Chaos) waitForRecovery(clientset kube.Interface, reporter victims.VictimHealthReporter, readyBefore int32, timeout time.Duration) (time.Duration, error) {
+	start := time.Now()
+	var ready int32
+
+	// The first check waits for an interval, since the status of the victim
+	// may not reflect the termination yet
+	err := wait.PollUntilContextTimeout(context.TODO(), recoveryPollInterval, timeout, false, func(ctx context.Context) (bool, error) {
+		var err error
+		ready, _, err = reporter.Replicas(clientset)
+		if err != nil {
+			return false, err
+		}
+		return ready >= readyBefore, nil
+	})
+	if wait.Interrupted(err) {
+		return 0, fmt.Errorf("%s %s did not recover within %s: %d of %d replicas ready", c.Victim().Kind(), c.Victim().Name(), timeout, ready, readyBefore)
+	}
+	if err != nil {
+		return 0, errors.Wrapf(err, "Failed to check recovery of %s %s", c.Victim().Kind(), c.Victim().Name())
+	}
+
+	return time.Since(start), nil
+}
+
 // The termination type and value is processed here
-func (c *Chaos) terminate(clientset kube.Interface) error {
+// Temporary attacks, which do not kill pods, describe what they did in
+// the returned outcome
+func (c *Chaos) terminate(clientset kube.Interface) (string, error) {
 	killType, err := c.Victim().KillType(clientset)
 	if err != nil {
-		return errors.Wrapf(err, "Failed to check KillType label for %s %s", c.Victim().Kind(), c.Victim().Name())
+		return "", errors.Wrapf(err, "Failed to check KillType label for %s %s", c.Victim().Kind(), c.Victim().Name())
 	}
 
 	killValue, err := c.getKillValue(clientset)
 
-	// KillAll is the only kill type that does not require a kill-value
-	if killType != config.KillAllLabelValue && err != nil {
-		return err
+	if requiresKillValue(killType) && err != nil {
+		return "", err
 	}
 
 	// Validate killtype
 	switch killType {
 	case config.KillFixedLabelValue:
-		return c.Victim().DeleteRandomPods(clientset, killValue)
+		return c.killPods(clientset, killValue)
 	case config.KillAllLabelValue:
 		killNum, err := c.Victim().KillNumberForKillingAll(clientset)
 		if err != nil {
-			return err
+			return "", err
 		}
-		return c.Victim().DeleteRandomPods(clientset, killNum)
+		return c.killPods(clientset, killNum)
 	case config.KillRandomMaxLabelValue:
 		killNum, err := c.Victim().KillNumberForMaxPercentage(clientset, killValue)
 		if err != nil {
-			return err
+			return "", err
 		}
-		return c.Victim().DeleteRandomPods(clientset, killNum)
+		return c.killPods(clientset, killNum)
 	case config.KillFixedPercentageLabelValue:
 		killNum, err := c.Victim().KillNumberForFixedPercentage(clientset, killValue)
 		if err != nil {
-			return err
+			return "", err
+		}
+		return c.killPods(clientset, killNum)
+	case config.KillNetworkIsolationLabelValue:
+		isolator, ok := c.Victim().(victims.VictimNetworkIsolator)
+		if !ok {
+			return "", fmt.Errorf("%s %s does not support %s", c.Victim().Kind(), c.Victim().Name(), killType)
+		}
+		return isolator.IsolatePods(clientset)
+	case config.KillScaleDownLabelValue:
+		scaler, ok := c.Victim().(victims.VictimScaler)
+		if !ok {
+			return "", fmt.Errorf("%s %s does not support %s", c.Victim().Kind(), c.Victim().Name(), killType)
 		}
-		return c.Victim().DeleteRandomPods(clientset, killNum)
+		return scaler.ScaleDown(clientset, killValue)
+	case config.KillReadinessIsolationLabelValue:
+		isolator, ok := c.Victim().(victims.VictimReadinessIsolator)
+		if !ok {
+			return "", fmt.Errorf("%s %s does not support %s", c.Victim().Kind(), c.Victim().Name(), killType)
+		}
+		return isolator.IsolatePodReadiness(clientset)
+	default:
+		return "", fmt.Errorf("failed to recognize KillType label for %s %s", c.Victim().Kind(), c.Victim().Name())
+	}
+}
+
+// KillAll and the isolation attacks are the only kill types that do not
+// require a kill-value
+func requiresKillValue(killType string) bool {
+	switch killType {
+	case config.KillAllLabelValue, config.KillNetworkIsolationLabelValue, config.KillReadinessIsolationLabelValue:
+		return false
 	default:
-		return fmt.Errorf("failed to recognize KillType label for %s %s", c.Victim().Kind(), c.Victim().Name())
+		return true
 	}
 }
 
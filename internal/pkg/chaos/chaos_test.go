@@ -1,11 +1,16 @@
 package chaos
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/config/param"
+	"kube-monkey/internal/pkg/victims"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 
@@ -35,7 +40,7 @@ func (s *ChaosTestSuite) TestVerifyExecutionNotEnrolled() {
 func (s *ChaosTestSuite) TestVerifyExecutionBlacklisted() {
 	v := s.chaos.victim.(*VictimMock)
 	v.On("IsEnrolled", s.client).Return(true, nil)
-	v.On("IsBlacklisted").Return(true)
+	v.On("IsBlacklisted", s.client).Return(true)
 	err := s.chaos.verifyExecution(s.client)
 	v.AssertExpectations(s.T())
 	s.EqualError(err, v.Kind()+" "+v.Name()+" is blacklisted. Skipping")
@@ -44,8 +49,8 @@ func (s *ChaosTestSuite) TestVerifyExecutionBlacklisted() {
 func (s *ChaosTestSuite) TestVerifyExecutionNotWhitelisted() {
 	v := s.chaos.victim.(*VictimMock)
 	v.On("IsEnrolled", s.client).Return(true, nil)
-	v.On("IsBlacklisted").Return(false)
-	v.On("IsWhitelisted").Return(false)
+	v.On("IsBlacklisted", s.client).Return(false)
+	v.On("IsWhitelisted", s.client).Return(false)
 	err := s.chaos.verifyExecution(s.client)
 	v.AssertExpectations(s.T())
 	s.EqualError(err, v.Kind()+" "+v.Name()+" is not whitelisted. Skipping")
@@ -54,19 +59,128 @@ func (s *ChaosTestSuite) TestVerifyExecutionNotWhitelisted() {
 func (s *ChaosTestSuite) TestVerifyExecutionWhitelisted() {
 	v := s.chaos.victim.(*VictimMock)
 	v.On("IsEnrolled", s.client).Return(true, nil)
-	v.On("IsBlacklisted").Return(false)
-	v.On("IsWhitelisted").Return(true)
+	v.On("IsBlacklisted", s.client).Return(false)
+	v.On("IsWhitelisted", s.client).Return(true)
 	err := s.chaos.verifyExecution(s.client)
 	v.AssertExpectations(s.T())
 	s.NoError(err)
 }
 
+// Reports replicas like a Deployment
+type ReplicasVictimMock struct {
+	*VictimMock
+}
+
+func (vm ReplicasVictimMock) Replicas(clientset kube.Interface) (int32, int32, error) {
+	args := vm.Called(clientset)
+	return args.Get(0).(int32), args.Get(1).(int32), args.Error(2)
+}
+
+type ScenarioMock struct {
+	*VictimMock
+}
+
+func (sm ScenarioMock) Run(clientset kube.Interface) (string, []victims.AffectedWorkload, error) {
+	args := sm.Called(clientset)
+	return args.String(0), args.Get(1).([]victims.AffectedWorkload), args.Error(2)
+}
+
+type NodeVictimMock struct {
+	*VictimMock
+}
+
+func (nm NodeVictimMock) Drain(clientset kube.Interface) (string, []victims.AffectedWorkload, error) {
+	args := nm.Called(clientset)
+	return args.String(0), args.Get(1).([]victims.AffectedWorkload), args.Error(2)
+}
+
+func (s *ChaosTestSuite) TestDrainNode() {
+	nm := NodeVictimMock{NewVictimMock()}
+	s.chaos.victim = nm
+	affected := []victims.AffectedWorkload{{Kind: "ReplicaSet", Namespace: "default", Name: "app", Pods: []string{"app-1"}}}
+	nm.On("IsEnrolled", s.client).Return(true, nil)
+	nm.On("Drain", s.client).Return("Cordoned node node-1 and evicted 1 pods, then uncordoned it after 5m0s", affected, nil)
+
+	result := s.chaos.drainNode(s.client, nm)
+	nm.AssertExpectations(s.T())
+	s.NoError(result.Error())
+	s.Equal("Cordoned node node-1 and evicted 1 pods, then uncordoned it after 5m0s", result.Outcome())
+	s.Equal(affected, result.Affected())
+	s.Equal("ReplicaSet default/app lost 1 pods (app-1)", result.Affected()[0].String())
+}
+
+func (s *ChaosTestSuite) TestDrainNodeNotEnrolled() {
+	nm := NodeVictimMock{NewVictimMock()}
+	s.chaos.victim = nm
+	nm.On("IsEnrolled", s.client).Return(false, nil)
+
+	result := s.chaos.drainNode(s.client, nm)
+	nm.AssertNotCalled(s.T(), "Drain", s.client)
+	s.EqualError(result.Error(), nm.Kind()+" "+nm.Name()+" is no longer enrolled in kube-monkey. Skipping")
+}
+
+func (s *ChaosTestSuite) TestRunScenario() {
+	sm := ScenarioMock{NewVictimMock()}
+	s.chaos.victim = sm
+	affected := []victims.AffectedWorkload{{Kind: "v1.Deployment", Namespace: "default", Name: "app", Pods: []string{"app-1"}, Running: 3}}
+	sm.On("Run", s.client).Return("Node node-1 went down", affected, nil)
+
+	result := s.chaos.runScenario(s.client, sm)
+	sm.AssertExpectations(s.T())
+	s.NoError(result.Error())
+	s.Equal("Node node-1 went down", result.Outcome())
+	s.Equal(affected, result.Affected())
+	s.Equal("v1.Deployment default/app lost 1 of 3 pods (app-1)", result.Affected()[0].String())
+}
+
+func (s *ChaosTestSuite) TestVerifyExecutionNotHealthy() {
+	viper.Set(param.MinHealthyReplicas, "100%")
+	defer viper.Set(param.MinHealthyReplicas, "")
+
+	v := ReplicasVictimMock{NewVictimMock()}
+	s.chaos.victim = v
+	v.On("IsEnrolled", s.client).Return(true, nil)
+	v.On("IsBlacklisted", s.client).Return(false)
+	v.On("IsWhitelisted", s.client).Return(true)
+	v.On("Replicas", s.client).Return(int32(1), int32(3), nil)
+	err := s.chaos.verifyExecution(s.client)
+	v.AssertExpectations(s.T())
+	s.EqualError(err, v.Kind()+" "+v.Name()+" is not healthy: 1 of 3 replicas ready, but 100% required. Skipping")
+}
+
+func (s *ChaosTestSuite) TestVerifyExecutionHealthy() {
+	viper.Set(param.MinHealthyReplicas, "2")
+	defer viper.Set(param.MinHealthyReplicas, "")
+
+	v := ReplicasVictimMock{NewVictimMock()}
+	s.chaos.victim = v
+	v.On("IsEnrolled", s.client).Return(true, nil)
+	v.On("IsBlacklisted", s.client).Return(false)
+	v.On("IsWhitelisted", s.client).Return(true)
+	v.On("Replicas", s.client).Return(int32(2), int32(3), nil)
+	err := s.chaos.verifyExecution(s.client)
+	v.AssertExpectations(s.T())
+	s.NoError(err)
+}
+
+func (s *ChaosTestSuite) TestVerifyExecutionWithoutMinHealthy() {
+	v := ReplicasVictimMock{NewVictimMock()}
+	s.chaos.victim = v
+	v.On("IsEnrolled", s.client).Return(true, nil)
+	v.On("IsBlacklisted", s.client).Return(false)
+	v.On("IsWhitelisted", s.client).Return(true)
+	err := s.chaos.verifyExecution(s.client)
+	v.AssertNotCalled(s.T(), "Replicas", s.client)
+	s.NoError(err)
+}
+
 func (s *ChaosTestSuite) TestTerminateKillTypeError() {
 	v := s.chaos.victim.(*VictimMock)
 	err := errors.New("KillType Error")
 	v.On("KillType", s.client).Return("", err)
 
-	s.NotNil(s.chaos.terminate(s.client))
+	_, err = s.chaos.terminate(s.client)
+	s.NotNil(err)
 	v.AssertExpectations(s.T())
 }
 
@@ -75,7 +189,8 @@ func (s *ChaosTestSuite) TestTerminateKillValueError() {
 	errMsg := "KillValue Error"
 	v.On("KillType", s.client).Return(config.KillFixedLabelValue, nil)
 	v.On("KillValue", s.client).Return(0, errors.New(errMsg))
-	s.NotNil(s.chaos.terminate(s.client))
+	_, err := s.chaos.terminate(s.client)
+	s.NotNil(err)
 	v.AssertExpectations(s.T())
 }
 
@@ -85,17 +200,54 @@ func (s *ChaosTestSuite) TestTerminateKillFixed() {
 	v.On("KillType", s.client).Return(config.KillFixedLabelValue, nil)
 	v.On("KillValue", s.client).Return(killValue, nil)
 	v.On("DeleteRandomPods", s.client, killValue).Return(nil)
-	_ = s.chaos.terminate(s.client)
+	_, _ = s.chaos.terminate(s.client)
 	v.AssertExpectations(s.T())
 }
 
+// Signals a container of its pods like a victim with a kill target
+type ContainerKillerMock struct {
+	*VictimMock
+}
+
+func (cm ContainerKillerMock) KillTarget() string {
+	return "app"
+}
+
+func (cm ContainerKillerMock) KillContainers(clientset kube.Interface, executor victims.PodExecutor, killNum int) (string, error) {
+	args := cm.Called(clientset, executor, killNum)
+	return args.String(0), args.Error(1)
+}
+
+type fakePodExecutor struct{}
+
+func (fakePodExecutor) Exec(namespace, pod, container string, command []string) error {
+	return nil
+}
+
+func (s *ChaosTestSuite) TestTerminateKillContainers() {
+	defer func(create func() (victims.PodExecutor, error)) { newPodExecutor = create }(newPodExecutor)
+	newPodExecutor = func() (victims.PodExecutor, error) { return fakePodExecutor{}, nil }
+
+	cm := ContainerKillerMock{NewVictimMock()}
+	s.chaos.victim = cm
+	cm.On("KillType", s.client).Return(config.KillFixedLabelValue, nil)
+	cm.On("KillValue", s.client).Return(1, nil)
+	cm.On("KillContainers", s.client, fakePodExecutor{}, 1).Return("Sent SIGTERM to container app of pod app-1, which the kubelet restarted", nil)
+
+	outcome, err := s.chaos.terminate(s.client)
+	s.NoError(err)
+	s.Equal("Sent SIGTERM to container app of pod app-1, which the kubelet restarted", outcome)
+	cm.AssertExpectations(s.T())
+	cm.AssertNotCalled(s.T(), "DeleteRandomPods", s.client, 1)
+}
+
 func (s *ChaosTestSuite) TestTerminateAllPods() {
 	v := s.chaos.victim.(*VictimMock)
 	v.On("KillType", s.client).Return(config.KillAllLabelValue, nil)
 	v.On("KillValue", s.client).Return(0, nil)
 	v.On("KillNumberForKillingAll", s.client).Return(0, nil)
 	v.On("DeleteRandomPods", s.client, 0).Return(nil)
-	_ = s.chaos.terminate(s.client)
+	_, _ = s.chaos.terminate(s.client)
 	v.AssertExpectations(s.T())
 }
 
@@ -106,7 +258,7 @@ func (s *ChaosTestSuite) TestTerminateKillRandomMaxPercentage() {
 	v.On("KillValue", s.client).Return(killValue, nil)
 	v.On("KillNumberForMaxPercentage", s.client, mock.AnythingOfType("int")).Return(0, nil)
 	v.On("DeleteRandomPods", s.client, 0).Return(nil)
-	_ = s.chaos.terminate(s.client)
+	_, _ = s.chaos.terminate(s.client)
 	v.AssertExpectations(s.T())
 }
 
@@ -117,15 +269,48 @@ func (s *ChaosTestSuite) TestTerminateKillFixedPercentage() {
 	v.On("KillValue", s.client).Return(killValue, nil)
 	v.On("KillNumberForFixedPercentage", s.client, mock.AnythingOfType("int")).Return(0, nil)
 	v.On("DeleteRandomPods", s.client, 0).Return(nil)
-	_ = s.chaos.terminate(s.client)
+	_, _ = s.chaos.terminate(s.client)
 	v.AssertExpectations(s.T())
 }
 
+func (s *ChaosTestSuite) TestTerminateNetworkIsolation() {
+	v := s.chaos.victim.(*VictimMock)
+	v.On("KillType", s.client).Return(config.KillNetworkIsolationLabelValue, nil)
+	v.On("KillValue", s.client).Return(0, errors.New("no kill-value"))
+	v.On("IsolatePods", s.client).Return("Isolated 2 pods for 5m0s", nil)
+	outcome, err := s.chaos.terminate(s.client)
+	v.AssertExpectations(s.T())
+	s.NoError(err)
+	s.Equal("Isolated 2 pods for 5m0s", outcome)
+}
+
+func (s *ChaosTestSuite) TestTerminateScaleDown() {
+	v := s.chaos.victim.(*VictimMock)
+	v.On("KillType", s.client).Return(config.KillScaleDownLabelValue, nil)
+	v.On("KillValue", s.client).Return(2, nil)
+	v.On("ScaleDown", s.client, 2).Return("Scaled down from 3 to 1 replicas for 5m0s", nil)
+	outcome, err := s.chaos.terminate(s.client)
+	v.AssertExpectations(s.T())
+	s.NoError(err)
+	s.Equal("Scaled down from 3 to 1 replicas for 5m0s", outcome)
+}
+
+func (s *ChaosTestSuite) TestTerminateReadinessIsolation() {
+	v := s.chaos.victim.(*VictimMock)
+	v.On("KillType", s.client).Return(config.KillReadinessIsolationLabelValue, nil)
+	v.On("KillValue", s.client).Return(0, errors.New("no kill-value"))
+	v.On("IsolatePodReadiness", s.client).Return("Removed label app from pod name-1 for 5m0s, then deleted the pod", nil)
+	outcome, err := s.chaos.terminate(s.client)
+	v.AssertExpectations(s.T())
+	s.NoError(err)
+	s.Equal("Removed label app from pod name-1 for 5m0s, then deleted the pod", outcome)
+}
+
 func (s *ChaosTestSuite) TestInvalidKillType() {
 	v := s.chaos.victim.(*VictimMock)
 	v.On("KillType", s.client).Return("InvalidKillTypeHere", nil)
 	v.On("KillValue", s.client).Return(0, nil)
-	err := s.chaos.terminate(s.client)
+	_, err := s.chaos.terminate(s.client)
 	v.AssertExpectations(s.T())
 	s.NotNil(err)
 }
@@ -146,6 +331,77 @@ func (s *ChaosTestSuite) TestGetKillValueReturnsError() {
 	s.NotNil(err)
 }
 
+func (s *ChaosTestSuite) TestResultBlockedByPDB() {
+	result := s.chaos.NewResult(errors.New("Generic failure"))
+	s.False(result.BlockedByPDB())
+
+	result = s.chaos.NewResult(&victims.BlockedByPDBError{Pod: "pod", Namespace: "default", Err: errors.New("Too many requests")})
+	s.True(result.BlockedByPDB())
+	s.EqualError(result.Error(), "eviction of pod default/pod blocked by PodDisruptionBudget: Too many requests")
+}
+
+func (s *ChaosTestSuite) TestWaitForRecovery() {
+	defer func(interval time.Duration) { recoveryPollInterval = interval }(recoveryPollInterval)
+	recoveryPollInterval = time.Millisecond
+
+	v := ReplicasVictimMock{NewVictimMock()}
+	s.chaos.victim = v
+	v.On("Replicas", s.client).Return(int32(2), int32(3), nil).Once()
+	v.On("Replicas", s.client).Return(int32(3), int32(3), nil)
+	recoveryTime, err := s.chaos.waitForRecovery(s.client, v, 3, time.Minute)
+	s.NoError(err)
+	s.Greater(recoveryTime, time.Duration(0))
+	v.AssertNumberOfCalls(s.T(), "Replicas", 2)
+}
+
+func (s *ChaosTestSuite) TestWaitForRecoveryTimeout() {
+	defer func(interval time.Duration) { recoveryPollInterval = interval }(recoveryPollInterval)
+	recoveryPollInterval = time.Millisecond
+
+	v := ReplicasVictimMock{NewVictimMock()}
+	s.chaos.victim = v
+	v.On("Replicas", s.client).Return(int32(1), int32(3), nil)
+	recoveryTime, err := s.chaos.waitForRecovery(s.client, v, 3, 20*time.Millisecond)
+	s.EqualError(err, v.Kind()+" "+v.Name()+" did not recover within 20ms: 1 of 3 replicas ready")
+	s.Equal(time.Duration(0), recoveryTime)
+}
+
+func (s *ChaosTestSuite) TestWaitForRecoveryError() {
+	defer func(interval time.Duration) { recoveryPollInterval = interval }(recoveryPollInterval)
+	recoveryPollInterval = time.Millisecond
+
+	v := ReplicasVictimMock{NewVictimMock()}
+	s.chaos.victim = v
+	v.On("Replicas", s.client).Return(int32(0), int32(0), errors.New("not found"))
+	_, err := s.chaos.waitForRecovery(s.client, v, 3, time.Minute)
+	s.EqualError(err, "Failed to check recovery of "+v.Kind()+" "+v.Name()+": not found")
+}
+
+func (s *ChaosTestSuite) TestScheduleCanceled() {
+	s.chaos.killAt = time.Now().Add(time.Hour)
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(errors.New("circuit breaker tripped"))
+
+	resultchan := make(chan *Result, 1)
+	s.chaos.Schedule(ctx, resultchan)
+	result := <-resultchan
+
+	s.True(result.Canceled())
+	s.EqualError(result.Error(), "termination canceled for "+s.chaos.Victim().Kind()+" "+s.chaos.Victim().Name()+": circuit breaker tripped")
+}
+
+func (s *ChaosTestSuite) TestResultCanceled() {
+	s.False(s.chaos.NewResult(nil).Canceled())
+	s.False(s.chaos.NewResult(errors.New("Generic failure")).Canceled())
+}
+
+func (s *ChaosTestSuite) TestResultRecovery() {
+	result := s.chaos.NewResult(nil)
+	s.False(result.RecoveryChecked())
+	s.Equal(time.Duration(0), result.RecoveryTime())
+	s.NoError(result.RecoveryError())
+}
+
 // Disabling test
 // See https://github.com/asobti/kube-monkey/issues/126
 //func (s *ChaosTestSuite) TestDurationToKillTime() {
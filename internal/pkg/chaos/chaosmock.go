@@ -62,16 +62,31 @@ func (vm *VictimMock) KillNumberForFixedPercentage(clientset kube.Interface, kil
 	return args.Int(0), args.Error(1)
 }
 
-func (vm *VictimMock) IsBlacklisted() bool {
-	args := vm.Called()
+func (vm *VictimMock) IsBlacklisted(clientset kube.Interface) bool {
+	args := vm.Called(clientset)
 	return args.Bool(0)
 }
 
-func (vm *VictimMock) IsWhitelisted() bool {
-	args := vm.Called()
+func (vm *VictimMock) IsWhitelisted(clientset kube.Interface) bool {
+	args := vm.Called(clientset)
 	return args.Bool(0)
 }
 
+func (vm *VictimMock) IsolatePods(clientset kube.Interface) (string, error) {
+	args := vm.Called(clientset)
+	return args.String(0), args.Error(1)
+}
+
+func (vm *VictimMock) ScaleDown(clientset kube.Interface, replicas int) (string, error) {
+	args := vm.Called(clientset, replicas)
+	return args.String(0), args.Error(1)
+}
+
+func (vm *VictimMock) IsolatePodReadiness(clientset kube.Interface) (string, error) {
+	args := vm.Called(clientset)
+	return args.String(0), args.Error(1)
+}
+
 func NewVictimMock() *VictimMock {
 	v := victims.New(KIND, NAME, NAMESPACE, IDENTIFIER, 1)
 	return &VictimMock{
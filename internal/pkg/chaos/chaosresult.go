@@ -1,12 +1,32 @@
 package chaos
 
 import (
+	"time"
+
+	"github.com/pkg/errors"
+
 	"kube-monkey/internal/pkg/victims"
 )
 
 type Result struct {
-	chaos *Chaos
-	err   error
+	chaos           *Chaos
+	err             error
+	outcome         string
+	recoveryChecked bool
+	recoveryTime    time.Duration
+	recoveryErr     error
+	affected        []victims.AffectedWorkload
+}
+
+// BlockedByPDB checks if the termination was refused by a PodDisruptionBudget
+// Error then returns the refused eviction
+func (r *Result) BlockedByPDB() bool {
+	return victims.IsBlockedByPDB(r.err)
+}
+
+// Canceled checks if the termination was canceled before its kill time
+func (r *Result) Canceled() bool {
+	return errors.Is(r.err, ErrCanceled)
 }
 
 func (r *Result) Victim() victims.Victim {
@@ -17,6 +37,49 @@ func (r *Result) Error() error {
 	return r.err
 }
 
+// Outcome describes what a temporary attack, such as a network isolation,
+// did to the victim, and how a victim that runs to completion, such as a Job,
+// finished after the termination. Empty for all other terminations
+func (r *Result) Outcome() string {
+	return r.outcome
+}
+
+// Appends to the outcome of the termination
+func (r *Result) addOutcome(outcome string) {
+	if outcome == "" {
+		return
+	}
+	if r.outcome != "" {
+		r.outcome += "; "
+	}
+	r.outcome += outcome
+}
+
+// Affected lists the workloads whose pods were terminated by a scenario,
+// such as a node outage. Empty for the termination of a single victim
+func (r *Result) Affected() []victims.AffectedWorkload {
+	return r.affected
+}
+
+// RecoveryChecked checks if kube-monkey watched the victim recover from
+// the termination
+func (r *Result) RecoveryChecked() bool {
+	return r.recoveryChecked
+}
+
+// RecoveryTime is how long the victim took after the termination to get
+// back to its ready replicas before the termination
+// Zero if the recovery was not checked or the victim did not recover
+func (r *Result) RecoveryTime() time.Duration {
+	return r.recoveryTime
+}
+
+// RecoveryError returns why the victim could not be verified to recover
+// from the termination, e.g. because it did not recover in time
+func (r *Result) RecoveryError() error {
+	return r.recoveryErr
+}
+
 // NewResult creates a new Result instance
 func NewResult(chaos *Chaos, err error) *Result {
 	return &Result{
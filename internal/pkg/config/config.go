@@ -1,6 +1,9 @@
 package config
 
 import (
+	"fmt"
+	"path"
+	"regexp"
 	"strings"
 	"time"
 
@@ -11,6 +14,9 @@ import (
 	"kube-monkey/internal/pkg/config/param"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
@@ -33,6 +39,88 @@ const (
 	KillFixedPercentageLabelValue = "fixed-percent"
 	KillFixedLabelValue           = "fixed"
 	KillAllLabelValue             = "kill-all"
+	StartHourLabelKey             = "kube-monkey/start-hour"
+	EndHourLabelKey               = "kube-monkey/end-hour"
+	TimezoneLabelKey              = "kube-monkey/time-zone"
+	GracePeriodLabelKey           = "kube-monkey/grace-period"
+	GracePeriodPodLabelValue      = "pod"
+
+	PodSelectionLabelKey                  = "kube-monkey/pod-selection"
+	PodSelectionRandomLabelValue          = "random"
+	PodSelectionOldestLabelValue          = "oldest"
+	PodSelectionNewestLabelValue          = "newest"
+	PodSelectionMostRestartedLabelValue   = "most-restarted"
+	PodSelectionMostCrowdedNodeLabelValue = "most-crowded-node"
+
+	PodDiscoveryIdentifier = "identifier"
+	PodDiscoverySelector   = "selector"
+
+	TerminationModeLabelKey = "kube-monkey/termination-mode"
+	TerminationModeDelete   = "delete"
+	TerminationModeEvict    = "evict"
+
+	MinHealthyReplicasLabelKey = "kube-monkey/min-healthy-replicas"
+
+	// Kill modes of temporary attacks, which are reverted after their
+	// attack duration rather than killing pods
+	KillNetworkIsolationLabelValue   = "network-isolation"
+	KillScaleDownLabelValue          = "scale-down"
+	KillReadinessIsolationLabelValue = "readiness-isolation"
+	AttackDurationLabelKey           = "kube-monkey/attack-duration"
+
+	// Records the replicas of a workload scaled down by an attack
+	OriginalReplicasAnnotationKey = "kube-monkey/original-replicas"
+
+	// The label removed from a pod by a readiness isolation, and whether
+	// the pod gets it back or is deleted after the attack
+	IsolationLabelLabelKey     = "kube-monkey/isolation-label"
+	IsolationCleanupLabelKey   = "kube-monkey/isolation-cleanup"
+	IsolationCleanupDelete     = "delete"
+	IsolationCleanupRestore    = "restore"
+	IsolatedLabelAnnotationKey = "kube-monkey/isolated-label"
+
+	// Resources created by kube-monkey for an attack carry this label, so
+	// they can be cleaned up if kube-monkey restarts during the attack
+	ManagedByLabelKey   = "app.kubernetes.io/managed-by"
+	ManagedByLabelValue = "kube-monkey"
+
+	// NetworkPolicies of network isolation attacks carry this label, which
+	// only kube-monkey sets, so that only they are removed at startup
+	IsolationPolicyLabelKey   = "kube-monkey/isolation"
+	IsolationPolicyLabelValue = "network"
+
+	// Zone outages group nodes by this label, and terminate at most the
+	// share of the pods of a victim set by ZoneOutageMaxPercentLabelKey
+	ZoneLabelKey                 = "topology.kubernetes.io/zone"
+	ZoneOutageMaxPercentLabelKey = "kube-monkey/zone-outage-max-percent"
+
+	// Kills target whole pods by default, or a container of the pods
+	// with KillTargetContainerPrefix followed by the container name
+	KillTargetLabelKey        = "kube-monkey/kill-target"
+	KillTargetPod             = "pod"
+	KillTargetContainerPrefix = "container:"
+	KillSignalLabelKey        = "kube-monkey/kill-signal"
+
+	// Nodes cordoned by a drain carry this annotation, so they are
+	// uncordoned if kube-monkey restarts during the attack
+	CordonedAnnotationKey = "kube-monkey/cordoned"
+
+	// Nodes opt in to drains, taints or both through NodeAttackLabelKey,
+	// a comma-separated list of attacks, and are drained by default
+	NodeAttackLabelKey = "kube-monkey/node-attack"
+	NodeAttackDrain    = "drain"
+	NodeAttackTaint    = "taint"
+
+	// Key of the NoExecute taint applied to nodes, which also lets
+	// kube-monkey find the taints left behind by a restart
+	TaintKey = "kube-monkey/chaos"
+
+	CircuitBreakerGlobal    = "global"
+	CircuitBreakerNamespace = "namespace"
+
+	// DefaultCircuitBreakerMessage is the notification message used when
+	// the circuit breaker receiver does not define one
+	DefaultCircuitBreakerMessage = `{"text": "kube-monkey canceled pending terminations, since {$kind} {$name} in {$namespace} did not recover: {$recovery}"}`
 )
 
 type Receiver struct {
@@ -62,6 +150,22 @@ func SetDefaults() {
 	viper.SetDefault(param.GracePeriodSec, 5)
 	viper.SetDefault(param.BlacklistedNamespaces, []string{metav1.NamespaceSystem})
 	viper.SetDefault(param.WhitelistedNamespaces, []string{metav1.NamespaceAll})
+	viper.SetDefault(param.BlacklistedNamespaceSelector, "")
+	viper.SetDefault(param.WhitelistedNamespaceSelector, "")
+	viper.SetDefault(param.JobCompletionTimeoutSec, 1800)
+	viper.SetDefault(param.PodDiscovery, PodDiscoveryIdentifier)
+	viper.SetDefault(param.VerifyPodOwners, false)
+	viper.SetDefault(param.CustomResources, []string{})
+	viper.SetDefault(param.TerminationMode, TerminationModeDelete)
+	viper.SetDefault(param.KillSignal, "TERM")
+	viper.SetDefault(param.MinHealthyReplicas, "")
+	viper.SetDefault(param.RecoveryTimeoutSec, 0)
+	viper.SetDefault(param.CircuitBreaker, "")
+	viper.SetDefault(param.AttackDurationSec, 300)
+	viper.SetDefault(param.NodeOutageMtbf, 0)
+	viper.SetDefault(param.ZoneOutageMtbf, 0)
+	viper.SetDefault(param.ZoneOutageMaxPercent, 100)
+	viper.SetDefault(param.NodeTaintMtbf, 0)
 
 	viper.SetDefault(param.DebugEnabled, false)
 	viper.SetDefault(param.DebugScheduleDelay, 30)
@@ -72,6 +176,7 @@ func SetDefaults() {
 	viper.SetDefault(param.NotificationsProxy, nil)
 	viper.SetDefault(param.NotificationsReportSchedule, false)
 	viper.SetDefault(param.NotificationsAttacks, Receiver{})
+	viper.SetDefault(param.NotificationsCircuitBreaker, Receiver{})
 }
 
 func setupWatch() {
@@ -146,6 +251,34 @@ func WhitelistedNamespaces() sets.String {
 	return sets.NewString(namespaces...)
 }
 
+// NamespaceMatches checks if the namespace matches any of the entries of a
+// namespace list. Entries are namespace names, globs such as "team-*-staging",
+// or regular expressions enclosed in slashes such as "/team-[a-z]+-staging/"
+// Regular expressions have to match the whole name
+func NamespaceMatches(entries sets.String, namespace string) bool {
+	if entries.Has(namespace) {
+		return true
+	}
+	for _, entry := range entries.UnsortedList() {
+		// Invalid entries are rejected by ValidateConfigs
+		if matched, err := matchNamespace(entry, namespace); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+func matchNamespace(entry, namespace string) (bool, error) {
+	if len(entry) > 1 && strings.HasPrefix(entry, "/") && strings.HasSuffix(entry, "/") {
+		re, err := regexp.Compile("^(?:" + entry[1:len(entry)-1] + ")$")
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(namespace), nil
+	}
+	return path.Match(entry, namespace)
+}
+
 func BlacklistEnabled() bool {
 	return !BlacklistedNamespaces().Equal(sets.NewString(metav1.NamespaceNone))
 }
@@ -154,6 +287,173 @@ func WhitelistEnabled() bool {
 	return !WhitelistedNamespaces().Equal(sets.NewString(metav1.NamespaceAll))
 }
 
+// BlacklistedNamespaceSelector returns the selector for blacklisted
+// namespaces, or nil if none is configured
+func BlacklistedNamespaceSelector() (labels.Selector, error) {
+	return namespaceSelector(param.BlacklistedNamespaceSelector)
+}
+
+// WhitelistedNamespaceSelector returns the selector for whitelisted
+// namespaces, or nil if none is configured
+func WhitelistedNamespaceSelector() (labels.Selector, error) {
+	return namespaceSelector(param.WhitelistedNamespaceSelector)
+}
+
+func namespaceSelector(key string) (labels.Selector, error) {
+	selector := strings.TrimSpace(viper.GetString(key))
+	if selector == "" {
+		return nil, nil
+	}
+	return labels.Parse(selector)
+}
+
+func JobCompletionTimeout() time.Duration {
+	timeoutSec := viper.GetInt(param.JobCompletionTimeoutSec)
+	return time.Duration(timeoutSec) * time.Second
+}
+
+// RecoveryTimeout returns how long to watch a victim recover from a
+// termination, or 0 if recovery is not verified
+func RecoveryTimeout() time.Duration {
+	timeoutSec := viper.GetInt(param.RecoveryTimeoutSec)
+	return time.Duration(timeoutSec) * time.Second
+}
+
+// CircuitBreaker returns which pending terminations are canceled once a
+// victim fails to recover, CircuitBreakerGlobal, CircuitBreakerNamespace,
+// or "" if the circuit breaker is disabled
+func CircuitBreaker() string {
+	return viper.GetString(param.CircuitBreaker)
+}
+
+// AttackDuration returns how long temporary attacks, such as
+// network isolation, last before they are reverted
+func AttackDuration() time.Duration {
+	durationSec := viper.GetInt(param.AttackDurationSec)
+	return time.Duration(durationSec) * time.Second
+}
+
+// NodeOutageMtbf returns the mean time between node outages in days,
+// or 0 if node outages are disabled
+func NodeOutageMtbf() float64 {
+	return viper.GetFloat64(param.NodeOutageMtbf)
+}
+
+// ZoneOutageMtbf returns the mean time between availability zone outages
+// in days, or 0 if zone outages are disabled
+func ZoneOutageMtbf() float64 {
+	return viper.GetFloat64(param.ZoneOutageMtbf)
+}
+
+// ZoneOutageMaxPercent returns the largest share of the running pods of a
+// victim, in percent, that a zone outage terminates
+func ZoneOutageMaxPercent() int {
+	return viper.GetInt(param.ZoneOutageMaxPercent)
+}
+
+// NodeTaintMtbf returns the mean time between node taints in days, or 0 if
+// node taints are disabled
+func NodeTaintMtbf() float64 {
+	return viper.GetFloat64(param.NodeTaintMtbf)
+}
+
+func PodDiscovery() string {
+	return viper.GetString(param.PodDiscovery)
+}
+
+func PodDiscoveryBySelector() bool {
+	return PodDiscovery() == PodDiscoverySelector
+}
+
+func VerifyPodOwners() bool {
+	return viper.GetBool(param.VerifyPodOwners)
+}
+
+func TerminationMode() string {
+	return viper.GetString(param.TerminationMode)
+}
+
+// IsValidTerminationMode checks if mode is a known termination mode
+func IsValidTerminationMode(mode string) bool {
+	return mode == TerminationModeDelete || mode == TerminationModeEvict
+}
+
+// KillSignal returns the signal sent to the containers targeted by
+// KillTargetLabelKey, without the SIG prefix
+func KillSignal() string {
+	return viper.GetString(param.KillSignal)
+}
+
+// IsValidKillSignal checks if signal is the name of a signal that can be sent
+// to a container, without the SIG prefix
+func IsValidKillSignal(signal string) bool {
+	switch signal {
+	case "HUP", "INT", "QUIT", "KILL", "USR1", "USR2", "TERM":
+		return true
+	}
+	return false
+}
+
+// MinHealthyReplicas returns the minimum of ready replicas required
+// for a termination, or nil if there is none
+func MinHealthyReplicas() (*intstr.IntOrString, error) {
+	return ParseMinHealthyReplicas(viper.GetString(param.MinHealthyReplicas))
+}
+
+// ParseMinHealthyReplicas parses a minimum of ready replicas, either
+// a number such as "2" or a percentage such as "80%"
+// Returns nil for an empty value
+func ParseMinHealthyReplicas(value string) (*intstr.IntOrString, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	minHealthy := intstr.Parse(value)
+	// Scaling to 100 yields the number itself, or the percentage
+	scaled, err := intstr.GetScaledValueFromIntOrPercent(&minHealthy, 100, true)
+	if err != nil {
+		return nil, fmt.Errorf("%q is neither a number nor a percentage", value)
+	}
+	if scaled < 0 || (minHealthy.Type == intstr.String && scaled > 100) {
+		return nil, fmt.Errorf("%q must be a non-negative number or a percentage in [0%%-100%%]", value)
+	}
+	return &minHealthy, nil
+}
+
+// CustomResources returns the additional resources that can be victims
+func CustomResources() ([]schema.GroupVersionResource, error) {
+	var gvrs []schema.GroupVersionResource
+	for _, resource := range viper.GetStringSlice(param.CustomResources) {
+		gvr, err := ParseGroupVersionResource(resource)
+		if err != nil {
+			return nil, err
+		}
+		gvrs = append(gvrs, gvr)
+	}
+	return gvrs, nil
+}
+
+// ParseGroupVersionResource parses a "group/version/resource" string,
+// or "version/resource" for resources in the core group
+func ParseGroupVersionResource(resource string) (schema.GroupVersionResource, error) {
+	parts := strings.Split(resource, "/")
+	for _, part := range parts {
+		if part == "" {
+			return schema.GroupVersionResource{}, fmt.Errorf("resource %q has an empty segment", resource)
+		}
+	}
+
+	switch len(parts) {
+	case 2:
+		return schema.GroupVersionResource{Version: parts[0], Resource: parts[1]}, nil
+	case 3:
+		return schema.GroupVersionResource{Group: parts[0], Version: parts[1], Resource: parts[2]}, nil
+	default:
+		return schema.GroupVersionResource{}, fmt.Errorf("resource %q is not of the form group/version/resource", resource)
+	}
+}
+
 func ClusterAPIServerHost() (string, bool) {
 	if viper.IsSet(param.ClusterAPIServerHost) {
 		return viper.GetString(param.ClusterAPIServerHost), true
@@ -198,3 +498,26 @@ func NotificationsAttacks() Receiver {
 	}
 	return receiver
 }
+
+// NotificationsCircuitBreaker returns the receiver of the notification sent
+// when the circuit breaker trips. The endpoint and headers fall back to
+// those of NotificationsAttacks, and the message to DefaultCircuitBreakerMessage
+func NotificationsCircuitBreaker() Receiver {
+	var receiver Receiver
+	err := viper.UnmarshalKey(param.NotificationsCircuitBreaker, &receiver)
+	if err != nil {
+		glog.Errorf("Failed to parse notifications.circuitBreaker %v", err)
+	}
+
+	if receiver.Endpoint == "" {
+		attacks := NotificationsAttacks()
+		receiver.Endpoint = attacks.Endpoint
+		if len(receiver.Headers) == 0 {
+			receiver.Headers = attacks.Headers
+		}
+	}
+	if receiver.Message == "" {
+		receiver.Message = DefaultCircuitBreakerMessage
+	}
+	return receiver
+}
@@ -11,6 +11,10 @@ import (
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/suite"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 type ConfigTestSuite struct {
@@ -32,12 +36,29 @@ func (s *ConfigTestSuite) TestSetDefaults() {
 	s.Equal(int64(5), viper.GetInt64(param.GracePeriodSec))
 	s.Equal([]string{metav1.NamespaceSystem}, viper.GetStringSlice(param.BlacklistedNamespaces))
 	s.Equal([]string{metav1.NamespaceAll}, viper.GetStringSlice(param.WhitelistedNamespaces))
+	s.Equal("", viper.GetString(param.BlacklistedNamespaceSelector))
+	s.Equal("", viper.GetString(param.WhitelistedNamespaceSelector))
+	s.Equal(1800, viper.GetInt(param.JobCompletionTimeoutSec))
+	s.Equal(PodDiscoveryIdentifier, viper.GetString(param.PodDiscovery))
+	s.False(viper.GetBool(param.VerifyPodOwners))
+	s.Equal([]string{}, viper.GetStringSlice(param.CustomResources))
+	s.Equal(TerminationModeDelete, viper.GetString(param.TerminationMode))
+	s.Equal("TERM", viper.GetString(param.KillSignal))
+	s.Equal("", viper.GetString(param.MinHealthyReplicas))
+	s.Equal(0, viper.GetInt(param.RecoveryTimeoutSec))
+	s.Equal("", viper.GetString(param.CircuitBreaker))
+	s.Equal(300, viper.GetInt(param.AttackDurationSec))
+	s.Equal(float64(0), viper.GetFloat64(param.NodeOutageMtbf))
+	s.Equal(float64(0), viper.GetFloat64(param.ZoneOutageMtbf))
+	s.Equal(100, viper.GetInt(param.ZoneOutageMaxPercent))
+	s.Equal(float64(0), viper.GetFloat64(param.NodeTaintMtbf))
 	s.False(viper.GetBool(param.DebugEnabled))
 	s.Equal(viper.GetInt(param.DebugScheduleDelay), 30)
 	s.False(viper.GetBool(param.DebugForceShouldKill))
 	s.False(viper.GetBool(param.DebugScheduleImmediateKill))
 	s.False(viper.GetBool(param.NotificationsEnabled))
 	s.Equal(Receiver{}, viper.Get(param.NotificationsAttacks))
+	s.Equal(Receiver{}, viper.Get(param.NotificationsCircuitBreaker))
 }
 
 func (s *ConfigTestSuite) TestDryRun() {
@@ -124,6 +145,143 @@ func (s *ConfigTestSuite) TestWhitelistEnabled() {
 	s.True(WhitelistEnabled())
 }
 
+func (s *ConfigTestSuite) TestNamespaceMatches() {
+	entries := sets.NewString("default", "team-*-staging", "/ops-[0-9]+/")
+	s.True(NamespaceMatches(entries, "default"))
+	s.True(NamespaceMatches(entries, "team-a-staging"))
+	s.False(NamespaceMatches(entries, "team-a-production"))
+	s.True(NamespaceMatches(entries, "ops-42"))
+	s.False(NamespaceMatches(entries, "ops-42-staging"), "Expected regular expressions to match the whole name")
+	s.False(NamespaceMatches(entries, "kube-system"))
+}
+
+func (s *ConfigTestSuite) TestNamespaceSelectors() {
+	selector, err := WhitelistedNamespaceSelector()
+	s.NoError(err)
+	s.Nil(selector)
+
+	viper.Set(param.WhitelistedNamespaceSelector, "env in (staging)")
+	selector, err = WhitelistedNamespaceSelector()
+	s.NoError(err)
+	s.True(selector.Matches(labels.Set{"env": "staging"}))
+	s.False(selector.Matches(labels.Set{"env": "production"}))
+
+	viper.Set(param.BlacklistedNamespaceSelector, "tier=critical")
+	selector, err = BlacklistedNamespaceSelector()
+	s.NoError(err)
+	s.True(selector.Matches(labels.Set{"tier": "critical"}))
+
+	viper.Set(param.BlacklistedNamespaceSelector, "tier in critical")
+	_, err = BlacklistedNamespaceSelector()
+	s.Error(err)
+}
+
+func (s *ConfigTestSuite) TestJobCompletionTimeout() {
+	viper.Set(param.JobCompletionTimeoutSec, 60)
+	s.Equal(60*time.Second, JobCompletionTimeout())
+}
+
+func (s *ConfigTestSuite) TestRecoveryTimeout() {
+	s.Equal(time.Duration(0), RecoveryTimeout())
+	viper.Set(param.RecoveryTimeoutSec, 300)
+	s.Equal(300*time.Second, RecoveryTimeout())
+}
+
+func (s *ConfigTestSuite) TestCircuitBreaker() {
+	s.Equal("", CircuitBreaker())
+	viper.Set(param.CircuitBreaker, CircuitBreakerNamespace)
+	s.Equal(CircuitBreakerNamespace, CircuitBreaker())
+}
+
+func (s *ConfigTestSuite) TestAttackDuration() {
+	s.Equal(5*time.Minute, AttackDuration())
+	viper.Set(param.AttackDurationSec, 60)
+	s.Equal(time.Minute, AttackDuration())
+}
+
+func (s *ConfigTestSuite) TestNodeOutageMtbf() {
+	s.Equal(float64(0), NodeOutageMtbf())
+	viper.Set(param.NodeOutageMtbf, 2.5)
+	s.Equal(2.5, NodeOutageMtbf())
+}
+
+func (s *ConfigTestSuite) TestZoneOutage() {
+	s.Equal(float64(0), ZoneOutageMtbf())
+	s.Equal(100, ZoneOutageMaxPercent())
+	viper.Set(param.ZoneOutageMtbf, 7)
+	viper.Set(param.ZoneOutageMaxPercent, 50)
+	s.Equal(float64(7), ZoneOutageMtbf())
+	s.Equal(50, ZoneOutageMaxPercent())
+}
+
+func (s *ConfigTestSuite) TestNodeTaintMtbf() {
+	s.Equal(float64(0), NodeTaintMtbf())
+	viper.Set(param.NodeTaintMtbf, 0.5)
+	s.Equal(0.5, NodeTaintMtbf())
+}
+
+func (s *ConfigTestSuite) TestPodDiscovery() {
+	s.False(PodDiscoveryBySelector())
+	viper.Set(param.PodDiscovery, PodDiscoverySelector)
+	s.Equal(PodDiscoverySelector, PodDiscovery())
+	s.True(PodDiscoveryBySelector())
+}
+
+func (s *ConfigTestSuite) TestVerifyPodOwners() {
+	viper.Set(param.VerifyPodOwners, true)
+	s.True(VerifyPodOwners())
+}
+
+func (s *ConfigTestSuite) TestTerminationMode() {
+	s.Equal(TerminationModeDelete, TerminationMode())
+	viper.Set(param.TerminationMode, TerminationModeEvict)
+	s.Equal(TerminationModeEvict, TerminationMode())
+	s.False(IsValidTerminationMode("drain"))
+}
+
+func (s *ConfigTestSuite) TestKillSignal() {
+	s.Equal("TERM", KillSignal())
+	viper.Set(param.KillSignal, "INT")
+	s.Equal("INT", KillSignal())
+	s.True(IsValidKillSignal("KILL"))
+	s.False(IsValidKillSignal("SIGTERM"))
+}
+
+func (s *ConfigTestSuite) TestMinHealthyReplicas() {
+	minHealthy, err := MinHealthyReplicas()
+	s.NoError(err)
+	s.Nil(minHealthy)
+
+	viper.Set(param.MinHealthyReplicas, "2")
+	minHealthy, err = MinHealthyReplicas()
+	s.NoError(err)
+	s.Equal(intstr.FromInt(2), *minHealthy)
+
+	viper.Set(param.MinHealthyReplicas, "80%")
+	minHealthy, err = MinHealthyReplicas()
+	s.NoError(err)
+	s.Equal(intstr.FromString("80%"), *minHealthy)
+
+	for _, value := range []string{"-1", "120%", "most"} {
+		_, err = ParseMinHealthyReplicas(value)
+		s.Error(err, "Expected an error for %s", value)
+	}
+}
+
+func (s *ConfigTestSuite) TestCustomResources() {
+	viper.Set(param.CustomResources, []string{"argoproj.io/v1alpha1/rollouts", "v1/pods"})
+	gvrs, err := CustomResources()
+	s.NoError(err)
+	s.Equal([]schema.GroupVersionResource{
+		{Group: "argoproj.io", Version: "v1alpha1", Resource: "rollouts"},
+		{Version: "v1", Resource: "pods"},
+	}, gvrs)
+
+	viper.Set(param.CustomResources, []string{"argoproj.io//rollouts"})
+	_, err = CustomResources()
+	s.Error(err)
+}
+
 func (s *ConfigTestSuite) TestClusterrAPIServerHost() {
 	host, enabled := ClusterAPIServerHost()
 	s.False(enabled)
@@ -179,6 +337,26 @@ func (s *ConfigTestSuite) TestNotificationsAttacks() {
 	s.Equal(receiver["headers"], actual.Headers)
 }
 
+func (s *ConfigTestSuite) TestNotificationsCircuitBreaker() {
+	receiver := map[string]interface{}{"endpoint": "endpoint2", "message": "message2", "headers": []string{"header3Key:header3Value"}}
+	viper.Set(param.NotificationsCircuitBreaker, receiver)
+	actual := NotificationsCircuitBreaker()
+
+	s.Equal(receiver["endpoint"], actual.Endpoint)
+	s.Equal(receiver["message"], actual.Message)
+	s.Equal(receiver["headers"], actual.Headers)
+}
+
+func (s *ConfigTestSuite) TestNotificationsCircuitBreakerDefaults() {
+	headers := []string{"header1Key:header1Value"}
+	viper.Set(param.NotificationsAttacks, map[string]interface{}{"endpoint": "endpoint1", "message": "message1", "headers": headers})
+	actual := NotificationsCircuitBreaker()
+
+	s.Equal("endpoint1", actual.Endpoint)
+	s.Equal(DefaultCircuitBreakerMessage, actual.Message)
+	s.Equal(headers, actual.Headers)
+}
+
 func TestSuite(t *testing.T) {
 	suite.Run(t, new(ConfigTestSuite))
 }
@@ -40,12 +40,16 @@ const (
 	// GracePeriodSec specifies the amount of time in
 	// seconds a pod is given to shut down gracefully,
 	// before Kubernetes does a hard kill
+	// Workloads can override it with the
+	// kube-monkey/grace-period setting
 	// Type: int
 	// Default: 5
 	GracePeriodSec = "kubemonkey.graceperiod_sec"
 
 	// WhitelistedNamespaces specifies a list of
 	// namespaces where terminations are valid
+	// Entries can be globs, e.g. "team-*-staging", or
+	// regular expressions enclosed in slashes
 	// Default is defined by metav1.NamespaceDefault
 	// To allow all namespaces use [""]
 	// Type: list
@@ -55,12 +59,163 @@ const (
 	// BlacklistedNamespaces specifies a list of namespaces
 	// for which terminations should never
 	// be carried out.
+	// Entries can be globs or regular expressions
+	// enclosed in slashes, as for WhitelistedNamespaces
 	// Default is defined by metav1.NamespaceSystem
 	// To block no namespaces use [""]
 	// Type: list
 	// Default: [ "kube-system" ]
 	BlacklistedNamespaces = "kubemonkey.blacklisted_namespaces"
 
+	// WhitelistedNamespaceSelector specifies a label selector,
+	// e.g. "env in (staging)", for namespaces where
+	// terminations are valid, in addition to the
+	// WhitelistedNamespaces
+	// Type: string
+	// Default: ""
+	WhitelistedNamespaceSelector = "kubemonkey.whitelisted_namespace_selector"
+
+	// BlacklistedNamespaceSelector specifies a label selector,
+	// e.g. "tier=critical", for namespaces where terminations
+	// should never be carried out, in addition to the
+	// BlacklistedNamespaces
+	// Type: string
+	// Default: ""
+	BlacklistedNamespaceSelector = "kubemonkey.blacklisted_namespace_selector"
+
+	// JobCompletionTimeoutSec specifies how long, in seconds,
+	// kube-monkey waits after a termination for a Job or CronJob
+	// victim to complete or exhaust its backoffLimit, before
+	// reporting the outcome as unknown
+	// Type: int
+	// Default: 1800
+	JobCompletionTimeoutSec = "kubemonkey.job_completion_timeout_sec"
+
+	// PodDiscovery specifies how the pods of a victim are found
+	// "identifier" selects the pods carrying the victim's
+	// kube-monkey/identifier label
+	// "selector" uses the victim's own spec.selector instead,
+	// which makes the identifier label optional. Victims without
+	// a selector, such as CronJobs and bare pods, keep using
+	// the identifier label
+	// Type: string
+	// Default: identifier
+	PodDiscovery = "kubemonkey.pod_discovery"
+
+	// VerifyPodOwners restricts the pods found by their selector
+	// to those whose ownerReferences lead back to the victim,
+	// directly or through a ReplicaSet or ReplicationController
+	// Guards against selectors overlapping between apps
+	// Type: bool
+	// Default: false
+	VerifyPodOwners = "kubemonkey.verify_pod_owners"
+
+	// CustomResources specifies a list of additional
+	// resources that can be victims, such as Argo Rollouts
+	// or OpenShift DeploymentConfigs. Each entry has the
+	// form "group/version/resource", or "version/resource"
+	// for the core group, e.g. "argoproj.io/v1alpha1/rollouts"
+	// The resources must have a spec.selector for their pods
+	// Type: list
+	// Default: []
+	CustomResources = "kubemonkey.custom_resources"
+
+	// TerminationMode specifies how pods are terminated
+	// "delete" deletes the pods, ignoring PodDisruptionBudgets
+	// "evict" uses the Eviction API instead, so that
+	// PodDisruptionBudgets can block a termination
+	// Workloads can override it with the
+	// kube-monkey/termination-mode setting
+	// Type: string
+	// Default: "delete"
+	TerminationMode = "kubemonkey.termination_mode"
+
+	// KillSignal specifies the signal sent to PID 1 of the
+	// container targeted by kube-monkey/kill-target, without
+	// the SIG prefix, e.g. "TERM" or "INT"
+	// Workloads can override it with the
+	// kube-monkey/kill-signal setting
+	// Type: string
+	// Default: "TERM"
+	KillSignal = "kubemonkey.kill_signal"
+
+	// MinHealthyReplicas specifies how many replicas of a
+	// Deployment, StatefulSet or DaemonSet must be ready
+	// for a termination to go ahead, either as a number,
+	// e.g. "2", or as a percentage of the desired replicas,
+	// e.g. "80%". Terminations of less healthy apps are skipped
+	// Workloads can override it with the
+	// kube-monkey/min-healthy-replicas setting
+	// To disable the check use ""
+	// Type: string
+	// Default: ""
+	MinHealthyReplicas = "kubemonkey.min_healthy_replicas"
+
+	// RecoveryTimeoutSec specifies how long, in seconds,
+	// kube-monkey watches a Deployment, StatefulSet or
+	// DaemonSet after a termination for its ready replicas
+	// to get back to their count before the termination
+	// The time to recover, or the failure to recover within
+	// the timeout, is reported with the result of the attack
+	// To disable the check use 0
+	// Type: int
+	// Default: 0
+	RecoveryTimeoutSec = "kubemonkey.recovery_timeout_sec"
+
+	// CircuitBreaker specifies which pending terminations
+	// are canceled once a victim fails to recover within
+	// the RecoveryTimeoutSec
+	// "global" cancels all terminations left for the day
+	// "namespace" only cancels those in the namespace of
+	// the victim that failed to recover
+	// To disable the circuit breaker use ""
+	// Type: string
+	// Default: ""
+	CircuitBreaker = "kubemonkey.circuit_breaker"
+
+	// AttackDurationSec specifies how long, in seconds,
+	// temporary attacks such as the network isolation of
+	// a victim last before they are reverted
+	// Workloads can override it with the
+	// kube-monkey/attack-duration setting
+	// Type: int
+	// Default: 300
+	AttackDurationSec = "kubemonkey.attack_duration_sec"
+
+	// NodeOutageMtbf specifies the mean time between node
+	// outages, in days. A node outage terminates all enrolled
+	// pods on a random node at once, across all kinds of victims
+	// To disable node outages use 0
+	// Type: float
+	// Default: 0
+	NodeOutageMtbf = "kubemonkey.node_outage_mtbf"
+
+	// ZoneOutageMtbf specifies the mean time between availability
+	// zone outages, in days. A zone outage terminates enrolled pods
+	// on all nodes of a random zone at once, up to the share of
+	// each victim allowed by ZoneOutageMaxPercent
+	// To disable zone outages use 0
+	// Type: float
+	// Default: 0
+	ZoneOutageMtbf = "kubemonkey.zone_outage_mtbf"
+
+	// ZoneOutageMaxPercent specifies the largest share of the
+	// running pods of a victim, in percent, that a zone outage
+	// terminates. Victims can override it with the
+	// kube-monkey/zone-outage-max-percent label or annotation
+	// Type: int
+	// Default: 100
+	ZoneOutageMaxPercent = "kubemonkey.zone_outage_max_percent"
+
+	// NodeTaintMtbf specifies the mean time between NoExecute
+	// taints of a random node opted in to chaos, in days. The
+	// taint evicts the pods that do not tolerate it, and is
+	// removed after the attack duration of the node
+	// To disable node taints use 0
+	// Type: float
+	// Default: 0
+	NodeTaintMtbf = "kubemonkey.node_taint_mtbf"
+
 	// ClusterAPIServerHost specifies the host URL for Kubernetes
 	// cluster APIServer. Use this config if the apiserver IP
 	// address provided by in-cluster config
@@ -118,4 +273,12 @@ const (
 	// Type: config.Receiver struct
 	// Default: Receiver{}
 	NotificationsAttacks = "notifications.attacks"
+
+	// NotificationsCircuitBreaker reports to an HTTP endpoint
+	// that the circuit breaker canceled pending terminations
+	// The endpoint and headers default to those of
+	// NotificationsAttacks
+	// Type: config.Receiver struct
+	// Default: Receiver{}
+	NotificationsCircuitBreaker = "notifications.circuitBreaker"
 )
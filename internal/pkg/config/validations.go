@@ -5,6 +5,8 @@ import (
 	"regexp"
 
 	"kube-monkey/internal/pkg/config/param"
+
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 func ValidateConfigs() error {
@@ -36,12 +38,90 @@ func ValidateConfigs() error {
 		return fmt.Errorf("RunHour: %s should be less than %s", param.RunHour, param.StartHour)
 	}
 
-	notificationsReceiver := NotificationsAttacks()
+	// Namespace lists should only contain valid globs and regular expressions
+	if err := validateNamespacePatterns(BlacklistedNamespaces()); err != nil {
+		return fmt.Errorf("BlacklistedNamespaces: %s %v", param.BlacklistedNamespaces, err)
+	}
+	if err := validateNamespacePatterns(WhitelistedNamespaces()); err != nil {
+		return fmt.Errorf("WhitelistedNamespaces: %s %v", param.WhitelistedNamespaces, err)
+	}
+
+	// Namespace selectors should be valid label selectors
+	if _, err := WhitelistedNamespaceSelector(); err != nil {
+		return fmt.Errorf("WhitelistedNamespaceSelector: %s %v", param.WhitelistedNamespaceSelector, err)
+	}
+	if _, err := BlacklistedNamespaceSelector(); err != nil {
+		return fmt.Errorf("BlacklistedNamespaceSelector: %s %v", param.BlacklistedNamespaceSelector, err)
+	}
+
+	// PodDiscovery should be a known mode
+	podDiscovery := PodDiscovery()
+	if podDiscovery != PodDiscoveryIdentifier && podDiscovery != PodDiscoverySelector {
+		return fmt.Errorf("PodDiscovery: %s must be %s or %s", param.PodDiscovery, PodDiscoveryIdentifier, PodDiscoverySelector)
+	}
+
+	// TerminationMode should be a known mode
+	if !IsValidTerminationMode(TerminationMode()) {
+		return fmt.Errorf("TerminationMode: %s must be %s or %s", param.TerminationMode, TerminationModeDelete, TerminationModeEvict)
+	}
+
+	// KillSignal should be a known signal
+	if !IsValidKillSignal(KillSignal()) {
+		return fmt.Errorf("KillSignal: %s must be HUP, INT, QUIT, KILL, USR1, USR2 or TERM", param.KillSignal)
+	}
+
+	// MinHealthyReplicas should be a number or a percentage
+	if _, err := MinHealthyReplicas(); err != nil {
+		return fmt.Errorf("MinHealthyReplicas: %s %v", param.MinHealthyReplicas, err)
+	}
+
+	// RecoveryTimeout should not be negative
+	if RecoveryTimeout() < 0 {
+		return fmt.Errorf("RecoveryTimeout: %s must not be negative", param.RecoveryTimeoutSec)
+	}
+
+	// AttackDuration should be positive
+	if !(AttackDuration() > 0) {
+		return fmt.Errorf("AttackDuration: %s must be positive", param.AttackDurationSec)
+	}
+
+	// NodeOutageMtbf should not be negative
+	if NodeOutageMtbf() < 0 {
+		return fmt.Errorf("NodeOutageMtbf: %s must not be negative", param.NodeOutageMtbf)
+	}
+
+	// ZoneOutageMtbf should not be negative
+	if ZoneOutageMtbf() < 0 {
+		return fmt.Errorf("ZoneOutageMtbf: %s must not be negative", param.ZoneOutageMtbf)
+	}
+
+	// NodeTaintMtbf should not be negative
+	if NodeTaintMtbf() < 0 {
+		return fmt.Errorf("NodeTaintMtbf: %s must not be negative", param.NodeTaintMtbf)
+	}
+
+	// ZoneOutageMaxPercent should be a percentage
+	if percent := ZoneOutageMaxPercent(); percent < 0 || percent > 100 {
+		return fmt.Errorf("ZoneOutageMaxPercent: %s must be between 0 and 100", param.ZoneOutageMaxPercent)
+	}
+
+	// CircuitBreaker should be a known scope
+	circuitBreaker := CircuitBreaker()
+	if circuitBreaker != "" && circuitBreaker != CircuitBreakerGlobal && circuitBreaker != CircuitBreakerNamespace {
+		return fmt.Errorf("CircuitBreaker: %s must be empty, %s or %s", param.CircuitBreaker, CircuitBreakerGlobal, CircuitBreakerNamespace)
+	}
+
+	// CustomResources should be group/version/resource
+	if _, err := CustomResources(); err != nil {
+		return fmt.Errorf("CustomResources: %s %v", param.CustomResources, err)
+	}
 
 	// Notification headers should be in a valid format
-	for _, header := range notificationsReceiver.Headers {
-		if !isValidHeader(header) {
-			return fmt.Errorf("Header: %s is not in valid format", header)
+	for _, notificationsReceiver := range []Receiver{NotificationsAttacks(), NotificationsCircuitBreaker()} {
+		for _, header := range notificationsReceiver.Headers {
+			if !isValidHeader(header) {
+				return fmt.Errorf("Header: %s is not in valid format", header)
+			}
 		}
 	}
 
@@ -52,6 +132,15 @@ func IsValidHour(hour int) bool {
 	return hour >= 0 && hour < 24
 }
 
+func validateNamespacePatterns(entries sets.String) error {
+	for _, entry := range entries.List() {
+		if _, err := matchNamespace(entry, ""); err != nil {
+			return fmt.Errorf("entry %q: %v", entry, err)
+		}
+	}
+	return nil
+}
+
 func isValidHeader(header string) bool {
 	re := regexp.MustCompile("^(.+:.+)$")
 
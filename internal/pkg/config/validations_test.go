@@ -32,6 +32,82 @@ func TestValidateConfigs(t *testing.T) {
 
 	viper.Set(param.RunHour, 23)
 	assert.EqualError(t, ValidateConfigs(), "RunHour: "+param.RunHour+" should be less than "+param.StartHour)
+	viper.Set(param.RunHour, 8)
+
+	viper.Set(param.WhitelistedNamespaces, []string{"team-*-staging", "/ops-[0-9]+/"})
+	assert.Nil(t, ValidateConfigs())
+	viper.Set(param.WhitelistedNamespaces, []string{"team-[-staging"})
+	assert.EqualError(t, ValidateConfigs(), "WhitelistedNamespaces: "+param.WhitelistedNamespaces+" entry \"team-[-staging\": syntax error in pattern")
+	viper.Set(param.WhitelistedNamespaces, []string{""})
+	viper.Set(param.BlacklistedNamespaces, []string{"/ops-(/"})
+	assert.Error(t, ValidateConfigs())
+	viper.Set(param.BlacklistedNamespaces, []string{"kube-system"})
+
+	viper.Set(param.WhitelistedNamespaceSelector, "env in (staging)")
+	viper.Set(param.BlacklistedNamespaceSelector, "tier=critical")
+	assert.Nil(t, ValidateConfigs())
+	viper.Set(param.WhitelistedNamespaceSelector, "env in staging")
+	assert.EqualError(t, ValidateConfigs(), "WhitelistedNamespaceSelector: "+param.WhitelistedNamespaceSelector+" unable to parse requirement: found 'staging' expected: '('")
+	viper.Set(param.WhitelistedNamespaceSelector, "")
+	viper.Set(param.BlacklistedNamespaceSelector, "tier in critical")
+	assert.Error(t, ValidateConfigs())
+	viper.Set(param.BlacklistedNamespaceSelector, "")
+
+	viper.Set(param.PodDiscovery, "labels")
+	assert.EqualError(t, ValidateConfigs(), "PodDiscovery: "+param.PodDiscovery+" must be identifier or selector")
+	viper.Set(param.PodDiscovery, PodDiscoverySelector)
+	assert.Nil(t, ValidateConfigs())
+	viper.Set(param.PodDiscovery, PodDiscoveryIdentifier)
+
+	viper.Set(param.TerminationMode, "drain")
+	assert.EqualError(t, ValidateConfigs(), "TerminationMode: "+param.TerminationMode+" must be delete or evict")
+	viper.Set(param.TerminationMode, TerminationModeEvict)
+	assert.Nil(t, ValidateConfigs())
+	viper.Set(param.TerminationMode, TerminationModeDelete)
+
+	viper.Set(param.KillSignal, "SIGTERM")
+	assert.EqualError(t, ValidateConfigs(), "KillSignal: "+param.KillSignal+" must be HUP, INT, QUIT, KILL, USR1, USR2 or TERM")
+	viper.Set(param.KillSignal, "TERM")
+
+	viper.Set(param.MinHealthyReplicas, "80%")
+	assert.Nil(t, ValidateConfigs())
+	viper.Set(param.MinHealthyReplicas, "most")
+	assert.EqualError(t, ValidateConfigs(), "MinHealthyReplicas: "+param.MinHealthyReplicas+" \"most\" is neither a number nor a percentage")
+	viper.Set(param.MinHealthyReplicas, "")
+
+	viper.Set(param.RecoveryTimeoutSec, -1)
+	assert.EqualError(t, ValidateConfigs(), "RecoveryTimeout: "+param.RecoveryTimeoutSec+" must not be negative")
+	viper.Set(param.RecoveryTimeoutSec, 0)
+
+	viper.Set(param.AttackDurationSec, 0)
+	assert.EqualError(t, ValidateConfigs(), "AttackDuration: "+param.AttackDurationSec+" must be positive")
+	viper.Set(param.AttackDurationSec, 300)
+
+	viper.Set(param.NodeOutageMtbf, -1)
+	assert.EqualError(t, ValidateConfigs(), "NodeOutageMtbf: "+param.NodeOutageMtbf+" must not be negative")
+	viper.Set(param.NodeOutageMtbf, 0)
+
+	viper.Set(param.ZoneOutageMtbf, -1)
+	assert.EqualError(t, ValidateConfigs(), "ZoneOutageMtbf: "+param.ZoneOutageMtbf+" must not be negative")
+	viper.Set(param.ZoneOutageMtbf, 0)
+
+	viper.Set(param.NodeTaintMtbf, -1)
+	assert.EqualError(t, ValidateConfigs(), "NodeTaintMtbf: "+param.NodeTaintMtbf+" must not be negative")
+	viper.Set(param.NodeTaintMtbf, 0)
+
+	viper.Set(param.ZoneOutageMaxPercent, 101)
+	assert.EqualError(t, ValidateConfigs(), "ZoneOutageMaxPercent: "+param.ZoneOutageMaxPercent+" must be between 0 and 100")
+	viper.Set(param.ZoneOutageMaxPercent, 100)
+
+	viper.Set(param.CircuitBreaker, "everything")
+	assert.EqualError(t, ValidateConfigs(), "CircuitBreaker: "+param.CircuitBreaker+" must be empty, "+CircuitBreakerGlobal+" or "+CircuitBreakerNamespace)
+	viper.Set(param.CircuitBreaker, CircuitBreakerGlobal)
+	assert.Nil(t, ValidateConfigs())
+	viper.Set(param.CircuitBreaker, "")
+
+	viper.Set(param.CustomResources, []string{"rollouts"})
+	assert.EqualError(t, ValidateConfigs(), "CustomResources: "+param.CustomResources+" resource \"rollouts\" is not of the form group/version/resource")
+	viper.Set(param.CustomResources, []string{})
 
 }
 
@@ -0,0 +1,75 @@
+package kubemonkey
+
+import (
+	"context"
+	"fmt"
+
+	"kube-monkey/internal/pkg/config"
+)
+
+// circuitBreaker cancels the pending terminations of a schedule once a
+// victim fails to recover, either all of them or only those in the
+// namespace of the victim, depending on its scope
+type circuitBreaker struct {
+	scope      string
+	ctx        context.Context
+	cancel     context.CancelCauseFunc
+	namespaces map[string]context.Context
+	cancels    map[string]context.CancelCauseFunc
+	tripped    map[string]bool
+}
+
+func newCircuitBreaker(scope string) *circuitBreaker {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	return &circuitBreaker{
+		scope:      scope,
+		ctx:        ctx,
+		cancel:     cancel,
+		namespaces: map[string]context.Context{},
+		cancels:    map[string]context.CancelCauseFunc{},
+		tripped:    map[string]bool{},
+	}
+}
+
+// Context returns the context that cancels the pending terminations in
+// the namespace when the circuit breaker trips
+func (b *circuitBreaker) Context(namespace string) context.Context {
+	if b.scope != config.CircuitBreakerNamespace {
+		return b.ctx
+	}
+
+	if _, ok := b.namespaces[namespace]; !ok {
+		b.namespaces[namespace], b.cancels[namespace] = context.WithCancelCause(b.ctx)
+	}
+	return b.namespaces[namespace]
+}
+
+// Trip cancels the pending terminations affected by the failure of a victim
+// in the namespace to recover, as reported by err
+// Returns false if the circuit breaker is disabled or already tripped for
+// the namespace
+func (b *circuitBreaker) Trip(namespace string, err error) bool {
+	key := ""
+	cancel := b.cancel
+	switch b.scope {
+	case config.CircuitBreakerGlobal:
+	case config.CircuitBreakerNamespace:
+		key = namespace
+		b.Context(namespace)
+		cancel = b.cancels[namespace]
+	default:
+		return false
+	}
+
+	if b.tripped[key] {
+		return false
+	}
+	b.tripped[key] = true
+	cancel(fmt.Errorf("circuit breaker tripped: %v", err))
+	return true
+}
+
+// Close releases the contexts of the circuit breaker
+func (b *circuitBreaker) Close() {
+	b.cancel(context.Canceled)
+}
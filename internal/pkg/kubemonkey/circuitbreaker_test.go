@@ -0,0 +1,49 @@
+package kubemonkey
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"kube-monkey/internal/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	breaker := newCircuitBreaker("")
+	defer breaker.Close()
+
+	ctx := breaker.Context("default")
+	assert.False(t, breaker.Trip("default", errors.New("did not recover")))
+	assert.NoError(t, ctx.Err())
+}
+
+func TestCircuitBreakerGlobal(t *testing.T) {
+	breaker := newCircuitBreaker(config.CircuitBreakerGlobal)
+	defer breaker.Close()
+
+	ctx1 := breaker.Context("ns1")
+	ctx2 := breaker.Context("ns2")
+	assert.True(t, breaker.Trip("ns1", errors.New("did not recover")))
+	assert.False(t, breaker.Trip("ns2", errors.New("did not recover")))
+
+	assert.ErrorIs(t, ctx1.Err(), context.Canceled)
+	assert.ErrorIs(t, ctx2.Err(), context.Canceled)
+	assert.EqualError(t, context.Cause(ctx2), "circuit breaker tripped: did not recover")
+}
+
+func TestCircuitBreakerNamespace(t *testing.T) {
+	breaker := newCircuitBreaker(config.CircuitBreakerNamespace)
+	defer breaker.Close()
+
+	ctx1 := breaker.Context("ns1")
+	ctx2 := breaker.Context("ns2")
+	assert.True(t, breaker.Trip("ns1", errors.New("did not recover")))
+	assert.False(t, breaker.Trip("ns1", errors.New("did not recover")))
+
+	assert.EqualError(t, context.Cause(ctx1), "circuit breaker tripped: did not recover")
+	assert.NoError(t, ctx2.Err())
+	assert.True(t, breaker.Trip("ns2", errors.New("did not recover")))
+	assert.Error(t, ctx2.Err())
+}
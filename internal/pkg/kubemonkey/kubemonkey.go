@@ -12,6 +12,7 @@ import (
 	"kube-monkey/internal/pkg/kubernetes"
 	"kube-monkey/internal/pkg/notifications"
 	"kube-monkey/internal/pkg/schedule"
+	"kube-monkey/internal/pkg/victims/factory"
 )
 
 func durationToNextRun(runhour int, loc *time.Location) time.Duration {
@@ -29,10 +30,16 @@ func durationToNextRun(runhour int, loc *time.Location) time.Duration {
 func Run() error {
 	// Verify kubernetes client can be created and works before
 	// we enter execution loop
-	if _, err := kubernetes.CreateClient(); err != nil {
+	clientset, err := kubernetes.CreateClient()
+	if err != nil {
 		return err
 	}
 
+	// Revert temporary attacks that a restart interrupted
+	if err := factory.ReconcileAttacks(clientset); err != nil {
+		glog.Errorf("Failed to revert attacks interrupted by a restart. Error: %v", err)
+	}
+
 	var notificationsClient notifications.Client
 	if config.NotificationsEnabled() {
 		glog.V(1).Infof("Notifications enabled!")
@@ -65,9 +72,12 @@ func ScheduleTerminations(entries []*chaos.Chaos, notificationsClient notificati
 	resultchan := make(chan *chaos.Result)
 	defer close(resultchan)
 
+	breaker := newCircuitBreaker(config.CircuitBreaker())
+	defer breaker.Close()
+
 	// Spin off all terminations
 	for _, chaos := range entries {
-		go chaos.Schedule(resultchan)
+		go chaos.Schedule(breaker.Context(chaos.Victim().Namespace()), resultchan)
 	}
 
 	completedCount := 0
@@ -78,15 +88,39 @@ func ScheduleTerminations(entries []*chaos.Chaos, notificationsClient notificati
 	// Gather results
 	for completedCount < len(entries) {
 		result = <-resultchan
-		if result.Error() != nil {
+		if result.Canceled() {
+			glog.V(2).Infof("Termination for %s %s canceled. Error: %v", result.Victim().Kind(), result.Victim().Name(), result.Error().Error())
+		} else if result.BlockedByPDB() {
+			glog.V(2).Infof("Termination for %s %s blocked by PodDisruptionBudget. Error: %v", result.Victim().Kind(), result.Victim().Name(), result.Error().Error())
+		} else if result.Error() != nil {
 			glog.Errorf("Failed to execute termination for %s %s. Error: %v", result.Victim().Kind(), result.Victim().Name(), result.Error().Error())
 		} else {
 			glog.V(2).Infof("Termination successfully executed for %s %s\n", result.Victim().Kind(), result.Victim().Name())
 		}
-		if config.NotificationsEnabled() {
+
+		if result.Outcome() != "" {
+			glog.V(2).Infof("Outcome for %s %s: %s\n", result.Victim().Kind(), result.Victim().Name(), result.Outcome())
+		}
+		for _, affected := range result.Affected() {
+			glog.V(2).Infof("%s %s affected %s\n", result.Victim().Kind(), result.Victim().Name(), affected)
+		}
+		if result.RecoveryError() != nil {
+			glog.Errorf("Failed to verify recovery of %s %s. Error: %v", result.Victim().Kind(), result.Victim().Name(), result.RecoveryError().Error())
+		} else if result.RecoveryChecked() {
+			glog.V(2).Infof("%s %s recovered in %s\n", result.Victim().Kind(), result.Victim().Name(), result.RecoveryTime())
+		}
+		if config.NotificationsEnabled() && !result.Canceled() {
 			currentTime := time.Now()
 			notifications.ReportAttack(notificationsClient, result, currentTime)
 		}
+
+		// Stop further chaos once a victim fails to recover
+		if result.RecoveryError() != nil && breaker.Trip(result.Victim().Namespace(), result.RecoveryError()) {
+			glog.Errorf("Circuit breaker tripped by %s %s, canceling pending terminations (scope: %s)", result.Victim().Kind(), result.Victim().Name(), config.CircuitBreaker())
+			if config.NotificationsEnabled() {
+				notifications.ReportCircuitBreaker(notificationsClient, result, time.Now())
+			}
+		}
 		completedCount++
 		glog.V(4).Info("Status Update: ", len(entries)-completedCount, " scheduled terminations left.")
 	}
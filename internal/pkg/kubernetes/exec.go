@@ -0,0 +1,81 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/golang/glog"
+
+	corev1 "k8s.io/api/core/v1"
+	kube "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// ExecutorFactory opens the stream of an exec request, such as
+// remotecommand.NewSPDYExecutor
+type ExecutorFactory func(config *rest.Config, method string, url *url.URL) (remotecommand.Executor, error)
+
+// PodExecutor runs commands in containers through the pods/exec subresource
+type PodExecutor struct {
+	config      *rest.Config
+	client      rest.Interface
+	newExecutor ExecutorFactory
+}
+
+// NewPodExecutor creates a PodExecutor that builds exec requests with
+// client, the REST client of the core API group, and opens their streams
+// with newExecutor
+func NewPodExecutor(config *rest.Config, client rest.Interface, newExecutor ExecutorFactory) *PodExecutor {
+	return &PodExecutor{config: config, client: client, newExecutor: newExecutor}
+}
+
+// CreatePodExecutor creates a PodExecutor for the in-cluster apiserver
+func CreatePodExecutor() (*PodExecutor, error) {
+	config, err := inClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to generate in-cluster config: %v", err)
+	}
+
+	clientset, err := kube.NewForConfig(config)
+	if err != nil {
+		glog.Errorf("failed to create clientset in NewForConfig: %v", err)
+		return nil, err
+	}
+	return NewPodExecutor(config, clientset.CoreV1().RESTClient(), remotecommand.NewSPDYExecutor), nil
+}
+
+// Exec runs command in the container of the pod, and returns an error
+// carrying its stderr if it fails
+func (e *PodExecutor) Exec(namespace, pod, container string, command []string) error {
+	req := e.client.Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := e.newExecutor(e.config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+
+	var stdout, stderr bytes.Buffer
+	err = executor.StreamWithContext(context.TODO(), remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr})
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%v: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	restfake "k8s.io/client-go/rest/fake"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// Records the exec request, and writes to stderr before failing if err is set
+type fakeExecutor struct {
+	url *url.URL
+	err error
+}
+
+func (f *fakeExecutor) Stream(options remotecommand.StreamOptions) error {
+	return f.StreamWithContext(context.TODO(), options)
+}
+
+func (f *fakeExecutor) StreamWithContext(ctx context.Context, options remotecommand.StreamOptions) error {
+	if f.err != nil {
+		fmt.Fprint(options.Stderr, "kill: can't kill pid 1: Operation not permitted\n")
+	}
+	return f.err
+}
+
+func newFakePodExecutor(executor *fakeExecutor) *PodExecutor {
+	client := &restfake.RESTClient{
+		GroupVersion:         corev1.SchemeGroupVersion,
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+	}
+	return NewPodExecutor(&rest.Config{}, client, func(config *rest.Config, method string, url *url.URL) (remotecommand.Executor, error) {
+		executor.url = url
+		return executor, nil
+	})
+}
+
+func TestExec(t *testing.T) {
+	executor := &fakeExecutor{}
+	err := newFakePodExecutor(executor).Exec("default", "app-1", "app", []string{"kill", "-s", "TERM", "1"})
+	assert.NoError(t, err)
+
+	if assert.NotNil(t, executor.url) {
+		assert.Equal(t, "/namespaces/default/pods/app-1/exec", executor.url.Path)
+		query := executor.url.Query()
+		assert.Equal(t, "app", query.Get("container"))
+		assert.Equal(t, []string{"kill", "-s", "TERM", "1"}, query["command"])
+		assert.Equal(t, "true", query.Get("stderr"))
+	}
+}
+
+func TestExecError(t *testing.T) {
+	executor := &fakeExecutor{err: errors.New("command terminated with exit code 1")}
+	err := newFakePodExecutor(executor).Exec("default", "app-1", "app", []string{"kill", "-s", "TERM", "1"})
+	assert.EqualError(t, err, "command terminated with exit code 1: kill: can't kill pid 1: Operation not permitted")
+}
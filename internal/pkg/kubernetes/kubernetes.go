@@ -14,6 +14,7 @@ import (
 	cfg "kube-monkey/internal/pkg/config"
 
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
 	kube "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 )
@@ -33,17 +34,11 @@ func CreateClient() (*kube.Clientset, error) {
 
 // NewInClusterClient only creates an initialized instance of k8 clientset
 func NewInClusterClient() (*kube.Clientset, error) {
-	config, err := rest.InClusterConfig()
+	config, err := inClusterConfig()
 	if err != nil {
-		glog.Errorf("failed to obtain config from InClusterConfig: %v", err)
 		return nil, err
 	}
 
-	if apiserverHost, override := cfg.ClusterAPIServerHost(); override {
-		glog.V(5).Infof("API server host overridden to: %s\n", apiserverHost)
-		config.Host = apiserverHost
-	}
-
 	clientset, err := kube.NewForConfig(config)
 	if err != nil {
 		glog.Errorf("failed to create clientset in NewForConfig: %v", err)
@@ -52,6 +47,36 @@ func NewInClusterClient() (*kube.Clientset, error) {
 	return clientset, nil
 }
 
+// CreateDynamicClient creates an instance of the k8 dynamic client, used
+// for victims that are not known to the typed clientset
+func CreateDynamicClient() (dynamic.Interface, error) {
+	config, err := inClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to generate in-cluster config: %v", err)
+	}
+
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		glog.Errorf("failed to create dynamic client in NewForConfig: %v", err)
+		return nil, err
+	}
+	return client, nil
+}
+
+func inClusterConfig() (*rest.Config, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		glog.Errorf("failed to obtain config from InClusterConfig: %v", err)
+		return nil, err
+	}
+
+	if apiserverHost, override := cfg.ClusterAPIServerHost(); override {
+		glog.V(5).Infof("API server host overridden to: %s\n", apiserverHost)
+		config.Host = apiserverHost
+	}
+	return config, nil
+}
+
 func VerifyClient(client discovery.DiscoveryInterface) bool {
 	_, err := client.ServerVersion()
 	return err == nil
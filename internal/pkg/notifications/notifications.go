@@ -3,11 +3,13 @@ package notifications
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"kube-monkey/internal/pkg/chaos"
 	"kube-monkey/internal/pkg/config"
 	"kube-monkey/internal/pkg/schedule"
+	"kube-monkey/internal/pkg/victims"
 
 	"github.com/golang/glog"
 )
@@ -43,6 +45,9 @@ func ReportAttack(client Client, result *chaos.Result, time time.Time) bool {
 		errorString = result.Error().Error()
 	}
 	msg := ReplacePlaceholders(receiver.Message, result.Victim().Name(), result.Victim().Kind(), result.Victim().Namespace(), errorString, time, os.Getenv("KUBE_MONKEY_ID"))
+	recovery, recoveryTime := recoveryToStrings(result.RecoveryChecked(), result.RecoveryTime(), result.RecoveryError())
+	msg = ReplaceRecoveryPlaceholders(msg, recovery, recoveryTime)
+	msg = ReplaceOutcomePlaceholders(msg, result.Outcome(), affectedToString(result.Affected()))
 	glog.V(1).Infof("reporting attack for %s %s to %s with message %s\n", result.Victim().Kind(), result.Victim().Name(), receiver.Endpoint, msg)
 	if err := Send(client, receiver.Endpoint, msg, toHeaders(receiver.Headers)); err != nil {
 		glog.Errorf("error reporting attack for %s %s to %s with message %s, error: %v\n", result.Victim().Kind(), result.Victim().Name(), receiver.Endpoint, msg, err)
@@ -51,3 +56,34 @@ func ReportAttack(client Client, result *chaos.Result, time time.Time) bool {
 
 	return success
 }
+
+// ReportCircuitBreaker reports that the failure of the victim of result to
+// recover tripped the circuit breaker and canceled pending terminations
+func ReportCircuitBreaker(client Client, result *chaos.Result, time time.Time) bool {
+	success := true
+
+	receiver := config.NotificationsCircuitBreaker()
+	errorString := ""
+	if result.Error() != nil {
+		errorString = result.Error().Error()
+	}
+	msg := ReplacePlaceholders(receiver.Message, result.Victim().Name(), result.Victim().Kind(), result.Victim().Namespace(), errorString, time, os.Getenv("KUBE_MONKEY_ID"))
+	recovery, recoveryTime := recoveryToStrings(result.RecoveryChecked(), result.RecoveryTime(), result.RecoveryError())
+	msg = ReplaceRecoveryPlaceholders(msg, recovery, recoveryTime)
+	glog.V(1).Infof("reporting circuit breaker tripped by %s %s to %s with message %s\n", result.Victim().Kind(), result.Victim().Name(), receiver.Endpoint, msg)
+	if err := Send(client, receiver.Endpoint, msg, toHeaders(receiver.Headers)); err != nil {
+		glog.Errorf("error reporting circuit breaker tripped by %s %s to %s with message %s, error: %v\n", result.Victim().Kind(), result.Victim().Name(), receiver.Endpoint, msg, err)
+		success = false
+	}
+
+	return success
+}
+
+// Lists the affected workloads, separated by semicolons
+func affectedToString(affected []victims.AffectedWorkload) string {
+	workloads := make([]string, len(affected))
+	for i, workload := range affected {
+		workloads[i] = workload.String()
+	}
+	return strings.Join(workloads, "; ")
+}
@@ -23,6 +23,10 @@ const (
 	Date         = "{$date}"
 	Error        = "{$error}"
 	KubeMonkeyID = "{$kubemonkeyid}"
+	Recovery     = "{$recovery}"
+	RecoveryTime = "{$recoverytime}"
+	Outcome      = "{$outcome}"
+	Affected     = "{$affected}"
 )
 
 func toHeaders(headersArray []string) map[string]string {
@@ -67,6 +71,36 @@ func ReplacePlaceholders(msg string, name string, kind string, namespace string,
 	return msg
 }
 
+// ReplaceRecoveryPlaceholders replaces the placeholders describing how the
+// victim recovered from the attack
+func ReplaceRecoveryPlaceholders(msg string, recovery string, recoveryTime string) string {
+	msg = strings.Replace(msg, Recovery, recovery, -1)
+	msg = strings.Replace(msg, RecoveryTime, recoveryTime, -1)
+
+	return msg
+}
+
+// ReplaceOutcomePlaceholders replaces the placeholders describing what the
+// attack did, and which workloads it affected
+func ReplaceOutcomePlaceholders(msg string, outcome string, affected string) string {
+	msg = strings.Replace(msg, Outcome, outcome, -1)
+	msg = strings.Replace(msg, Affected, affected, -1)
+
+	return msg
+}
+
+// Describes the recovery of a victim as "recovered" and the seconds it took,
+// or as the failure to recover. Both are empty if the recovery was not checked
+func recoveryToStrings(checked bool, recoveryTime time.Duration, err error) (string, string) {
+	if !checked {
+		return "", ""
+	}
+	if err != nil {
+		return err.Error(), ""
+	}
+	return "recovered", strconv.FormatInt(int64(recoveryTime.Round(time.Second)/time.Second), 10)
+}
+
 func timeToEpoch(time time.Time) string {
 	epoch := time.UnixNano() / 1000000
 
@@ -1,6 +1,7 @@
 package notifications
 
 import (
+	"errors"
 	"os"
 	"testing"
 	"time"
@@ -110,3 +111,29 @@ func Test_MultiplePlaceholders(t *testing.T) {
 	actual := ReplacePlaceholders(msg, "testName", "", "", "", currentTime, "CLUSTER_A")
 	assert.Equal(t, `{"date1":"`+timeToDate(currentTime)+`","date2":"`+timeToDate(currentTime)+`","name":"testName"}`, actual)
 }
+
+func Test_RecoveryPlaceholders(t *testing.T) {
+	msg := `{"recovery":"{$recovery}","seconds":"{$recoverytime}"}`
+	actual := ReplaceRecoveryPlaceholders(msg, "recovered", "42")
+	assert.Equal(t, `{"recovery":"recovered","seconds":"42"}`, actual)
+}
+
+func Test_RecoveryToStrings(t *testing.T) {
+	recovery, recoveryTime := recoveryToStrings(false, 0, nil)
+	assert.Equal(t, "", recovery)
+	assert.Equal(t, "", recoveryTime)
+
+	recovery, recoveryTime = recoveryToStrings(true, 41600*time.Millisecond, nil)
+	assert.Equal(t, "recovered", recovery)
+	assert.Equal(t, "42", recoveryTime)
+
+	recovery, recoveryTime = recoveryToStrings(true, 0, errors.New("Deployment app did not recover within 5m0s"))
+	assert.Equal(t, "Deployment app did not recover within 5m0s", recovery)
+	assert.Equal(t, "", recoveryTime)
+}
+
+func Test_OutcomePlaceholders(t *testing.T) {
+	msg := `{"outcome":"{$outcome}","affected":"{$affected}"}`
+	actual := ReplaceOutcomePlaceholders(msg, "Node node-1 went down", "v1.Deployment default/app lost 2 of 3 pods (app-1, app-2)")
+	assert.Equal(t, `{"outcome":"Node node-1 went down","affected":"v1.Deployment default/app lost 2 of 3 pods (app-1, app-2)"}`, actual)
+}
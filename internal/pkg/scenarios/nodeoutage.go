@@ -0,0 +1,49 @@
+package scenarios
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/victims"
+
+	kube "k8s.io/client-go/kubernetes"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const NodeOutageKind = "NodeOutage"
+
+// NodeOutage simulates the failure of a node by terminating all enrolled
+// pods on a random node at once
+type NodeOutage struct {
+	scenario
+}
+
+// NewNodeOutage creates a node outage among the victims listed by list
+func NewNodeOutage(list Lister) *NodeOutage {
+	base := victims.New(NodeOutageKind, "random-node", metav1.NamespaceAll, "", config.NodeOutageMtbf())
+	return &NodeOutage{scenario{VictimBase: base, list: list}}
+}
+
+// Run picks a random node hosting running pods of eligible victims, and
+// terminates all those pods at once
+func (n *NodeOutage) Run(clientset kube.Interface) (string, []victims.AffectedWorkload, error) {
+	eligible, err := n.list()
+	if err != nil {
+		return "", nil, err
+	}
+
+	byNode := enrolledPodsByNode(clientset, eligible)
+	if len(byNode) == 0 {
+		return "", nil, fmt.Errorf("no node hosts running pods of eligible victims at the moment")
+	}
+
+	nodes := sortedKeys(byNode)
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	node := nodes[r.Intn(len(nodes))]
+
+	affected, err := terminateAll(clientset, byNode[node])
+	return fmt.Sprintf("Node %s went down", node), affected, err
+}
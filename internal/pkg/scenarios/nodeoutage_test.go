@@ -0,0 +1,120 @@
+package scenarios
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/victims"
+	"kube-monkey/internal/pkg/victims/factory/deployments"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kube "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const NAMESPACE = metav1.NamespaceDefault
+
+func newPod(name, identifier, node string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: NAMESPACE,
+			Labels:    map[string]string{config.IdentLabelKey: identifier},
+		},
+		Spec:   corev1.PodSpec{NodeName: node},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func newVictim(t *testing.T, identifier string) victims.Victim {
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      identifier,
+			Namespace: NAMESPACE,
+			Labels: map[string]string{
+				config.IdentLabelKey: identifier,
+				config.MtbfLabelKey:  "1",
+			},
+		},
+	}
+	victim, err := deployments.New(dep, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return victim
+}
+
+func lister(eligible ...victims.Victim) Lister {
+	return func() ([]victims.Victim, error) {
+		return eligible, nil
+	}
+}
+
+func podNames(client kube.Interface) []string {
+	pods, _ := client.CoreV1().Pods(NAMESPACE).List(context.TODO(), metav1.ListOptions{})
+	var names []string
+	for _, pod := range pods.Items {
+		names = append(names, pod.Name)
+	}
+	return names
+}
+
+func TestNodeOutage(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		newPod("app1-a", "app1", "node-1"),
+		newPod("app1-b", "app1", "node-1"),
+		newPod("app2-a", "app2", "node-1"),
+		newPod("other", "other", "node-1"),
+	)
+	outage := NewNodeOutage(lister(newVictim(t, "app1"), newVictim(t, "app2")))
+
+	outcome, affected, err := outage.Run(client)
+	assert.NoError(t, err)
+	assert.Equal(t, "Node node-1 went down", outcome)
+	assert.Equal(t, []victims.AffectedWorkload{
+		{Kind: "v1.Deployment", Namespace: NAMESPACE, Name: "app1", Pods: []string{"app1-a", "app1-b"}, Running: 2},
+		{Kind: "v1.Deployment", Namespace: NAMESPACE, Name: "app2", Pods: []string{"app2-a"}, Running: 1},
+	}, affected)
+	assert.Equal(t, []string{"other"}, podNames(client), "Expected only the pods of eligible victims to be terminated")
+}
+
+func TestNodeOutageWithoutPods(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	outage := NewNodeOutage(lister(newVictim(t, "app1")))
+
+	_, _, err := outage.Run(client)
+	assert.EqualError(t, err, "no node hosts running pods of eligible victims at the moment")
+}
+
+func TestNodeOutageListError(t *testing.T) {
+	outage := NewNodeOutage(func() ([]victims.Victim, error) {
+		return nil, errors.New("list failed")
+	})
+
+	_, _, err := outage.Run(fake.NewSimpleClientset())
+	assert.EqualError(t, err, "list failed")
+}
+
+func TestEnrolledPodsByNode(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		newPod("app1-a", "app1", "node-1"),
+		newPod("app1-b", "app1", "node-2"),
+		newPod("app1-c", "app1", ""),
+	)
+	app1 := newVictim(t, "app1")
+
+	// A pod matched by a second victim only belongs to the first
+	byNode := enrolledPodsByNode(client, []victims.Victim{app1, app1})
+	assert.Equal(t, []string{"node-1", "node-2"}, sortedKeys(byNode))
+	if assert.Len(t, byNode["node-1"], 1) {
+		assert.Equal(t, "app1-a", byNode["node-1"][0].pods[0].Name)
+	}
+	if assert.Len(t, byNode["node-2"], 1) {
+		assert.Equal(t, "app1-b", byNode["node-2"][0].pods[0].Name)
+	}
+}
@@ -0,0 +1,54 @@
+package scenarios
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/victims"
+
+	kube "k8s.io/client-go/kubernetes"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const NodeTaintKind = "NodeTaint"
+
+// NodeTaint applies a temporary NoExecute taint to a random node opted in
+// to chaos, evicting the pods that do not tolerate it
+type NodeTaint struct {
+	scenario
+}
+
+// NewNodeTaint creates a node taint among the nodes listed by list, such as
+// factory.EligibleTaintNodes
+func NewNodeTaint(list Lister) *NodeTaint {
+	base := victims.New(NodeTaintKind, "random-node", metav1.NamespaceAll, "", config.NodeTaintMtbf())
+	return &NodeTaint{scenario{VictimBase: base, list: list}}
+}
+
+// Run picks a random node that can be tainted and taints it
+func (n *NodeTaint) Run(clientset kube.Interface) (string, []victims.AffectedWorkload, error) {
+	eligible, err := n.list()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var nodes []victims.Victim
+	for _, node := range eligible {
+		if _, ok := node.(victims.VictimTainter); ok {
+			nodes = append(nodes, node)
+		}
+	}
+	if len(nodes) == 0 {
+		return "", nil, fmt.Errorf("no node is enrolled in kube-monkey at the moment")
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name() < nodes[j].Name() })
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	node := nodes[r.Intn(len(nodes))]
+
+	return node.(victims.VictimTainter).Taint(clientset)
+}
@@ -0,0 +1,46 @@
+package scenarios
+
+import (
+	"testing"
+
+	"kube-monkey/internal/pkg/victims"
+
+	"github.com/stretchr/testify/assert"
+	kube "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// Stands for a node that records whether it was tainted
+type taintableNode struct {
+	victims.Victim
+	name    string
+	tainted bool
+}
+
+func (n *taintableNode) Name() string {
+	return n.name
+}
+
+func (n *taintableNode) Taint(clientset kube.Interface) (string, []victims.AffectedWorkload, error) {
+	n.tainted = true
+	return "Tainted node " + n.name, nil, nil
+}
+
+func TestNodeTaint(t *testing.T) {
+	node := &taintableNode{name: "node-1"}
+	taint := NewNodeTaint(lister(newVictim(t, "app1"), node))
+
+	outcome, affected, err := taint.Run(fake.NewSimpleClientset())
+	assert.NoError(t, err)
+	assert.Equal(t, "Tainted node node-1", outcome)
+	assert.Empty(t, affected)
+	assert.True(t, node.tainted, "Expected the only node to be tainted")
+}
+
+func TestNodeTaintWithoutNodes(t *testing.T) {
+	// Workloads cannot be tainted
+	taint := NewNodeTaint(lister(newVictim(t, "app1")))
+
+	_, _, err := taint.Run(fake.NewSimpleClientset())
+	assert.EqualError(t, err, "no node is enrolled in kube-monkey at the moment")
+}
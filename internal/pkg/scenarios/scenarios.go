@@ -0,0 +1,134 @@
+/*
+Package scenarios provides outages that span several workloads, such as
+the loss of a node, and are scheduled next to the victims of the factory
+*/
+package scenarios
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/golang/glog"
+
+	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/victims"
+
+	kube "k8s.io/client-go/kubernetes"
+
+	corev1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// Lister lists the victims eligible for chaos, such as factory.EligibleVictims
+type Lister func() ([]victims.Victim, error)
+
+// scenario implements the methods of victims.Victim that do not apply to
+// scenarios, which check the eligibility of their workloads themselves
+type scenario struct {
+	*victims.VictimBase
+	list Lister
+}
+
+func (s *scenario) IsEnrolled(clientset kube.Interface) (bool, error) {
+	return true, nil
+}
+
+func (s *scenario) KillType(clientset kube.Interface) (string, error) {
+	return "", fmt.Errorf("%s %s does not have a %s", s.Kind(), s.Name(), config.KillTypeLabelKey)
+}
+
+func (s *scenario) KillValue(clientset kube.Interface) (int, error) {
+	return -1, fmt.Errorf("%s %s does not have a %s", s.Kind(), s.Name(), config.KillValueLabelKey)
+}
+
+// victimPods are running pods of a victim, out of all its running pods
+type victimPods struct {
+	victim  victims.Victim
+	pods    []corev1.Pod
+	running int
+}
+
+// Groups the running pods of the eligible victims by the node they run on
+// A pod matched by several victims only belongs to the first of them
+func enrolledPodsByNode(clientset kube.Interface, eligible []victims.Victim) map[string][]victimPods {
+	seen := map[string]bool{}
+	byNode := map[string][]victimPods{}
+
+	for _, victim := range eligible {
+		pods, err := victim.RunningPods(clientset)
+		if err != nil {
+			glog.Warningf("Skipping %s %s because of error: %v", victim.Kind(), victim.Name(), err)
+			continue
+		}
+
+		running := len(pods)
+		nodePods := map[string][]corev1.Pod{}
+		for _, pod := range pods {
+			key := pod.Namespace + "/" + pod.Name
+			if pod.Spec.NodeName == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			nodePods[pod.Spec.NodeName] = append(nodePods[pod.Spec.NodeName], pod)
+		}
+
+		for node, pods := range nodePods {
+			byNode[node] = append(byNode[node], victimPods{victim: victim, pods: pods, running: running})
+		}
+	}
+	return byNode
+}
+
+// Returns the keys of the map in order, to pick from them at random
+func sortedKeys(m map[string][]victimPods) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Terminates all the pods at once, and returns the workloads that lost pods
+func terminateAll(clientset kube.Interface, targets []victimPods) ([]victims.AffectedWorkload, error) {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var errs []error
+	terminated := make([][]string, len(targets))
+
+	for i, target := range targets {
+		for _, pod := range target.pods {
+			wg.Add(1)
+			go func(i int, victim victims.Victim, pod string) {
+				defer wg.Done()
+				err := victim.DeletePod(clientset, pod)
+
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					errs = append(errs, fmt.Errorf("failed to terminate pod %s of %s %s: %v", pod, victim.Kind(), victim.Name(), err))
+					return
+				}
+				terminated[i] = append(terminated[i], pod)
+			}(i, target.victim, pod.Name)
+		}
+	}
+	wg.Wait()
+
+	var affected []victims.AffectedWorkload
+	for i, target := range targets {
+		if len(terminated[i]) == 0 {
+			continue
+		}
+		sort.Strings(terminated[i])
+		affected = append(affected, victims.AffectedWorkload{
+			Kind:      target.victim.Kind(),
+			Namespace: target.victim.Namespace(),
+			Name:      target.victim.Name(),
+			Pods:      terminated[i],
+			Running:   target.running,
+		})
+	}
+	return affected, utilerrors.NewAggregate(errs)
+}
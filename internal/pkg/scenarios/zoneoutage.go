@@ -0,0 +1,133 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/golang/glog"
+
+	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/victims"
+
+	kube "k8s.io/client-go/kubernetes"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const ZoneOutageKind = "ZoneOutage"
+
+// ZoneOutage simulates the failure of an availability zone by terminating
+// the enrolled pods on all nodes of a random zone at once, up to the share
+// of each victim allowed by its zone outage cap
+type ZoneOutage struct {
+	scenario
+}
+
+// NewZoneOutage creates a zone outage among the victims listed by list
+func NewZoneOutage(list Lister) *ZoneOutage {
+	base := victims.New(ZoneOutageKind, "random-zone", metav1.NamespaceAll, "", config.ZoneOutageMtbf())
+	return &ZoneOutage{scenario{VictimBase: base, list: list}}
+}
+
+// Run picks a random zone hosting running pods of eligible victims, and
+// terminates those pods at once. Each victim loses at most its
+// ZoneOutageMaxPercent share of its running pods, the rest are spared
+func (z *ZoneOutage) Run(clientset kube.Interface) (string, []victims.AffectedWorkload, error) {
+	eligible, err := z.list()
+	if err != nil {
+		return "", nil, err
+	}
+
+	zones, err := nodeZones(clientset)
+	if err != nil {
+		return "", nil, err
+	}
+
+	byZone := map[string][]victimPods{}
+	byNode := enrolledPodsByNode(clientset, eligible)
+	for _, node := range sortedKeys(byNode) {
+		zone, ok := zones[node]
+		if !ok {
+			glog.V(5).Infof("Skipping node %s without %s label", node, config.ZoneLabelKey)
+			continue
+		}
+		byZone[zone] = mergeVictimPods(byZone[zone], byNode[node])
+	}
+	if len(byZone) == 0 {
+		return "", nil, fmt.Errorf("no availability zone hosts running pods of eligible victims at the moment")
+	}
+
+	names := sortedKeys(byZone)
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	zone := names[r.Intn(len(names))]
+
+	targets, spared := capVictimPods(byZone[zone], r)
+	affected, err := terminateAll(clientset, targets)
+
+	outcome := fmt.Sprintf("Zone %s went down", zone)
+	if spared > 0 {
+		outcome += fmt.Sprintf(", sparing %d pods to respect the zone outage caps of their victims", spared)
+	}
+	return outcome, affected, err
+}
+
+// Maps the names of the nodes to their availability zone, leaving out the
+// nodes without config.ZoneLabelKey label
+func nodeZones(clientset kube.Interface) (map[string]string, error) {
+	nodes, err := clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{LabelSelector: config.ZoneLabelKey})
+	if err != nil {
+		return nil, err
+	}
+
+	zones := map[string]string{}
+	for _, node := range nodes.Items {
+		if zone := node.Labels[config.ZoneLabelKey]; zone != "" {
+			zones[node.Name] = zone
+		}
+	}
+	return zones, nil
+}
+
+// Adds the pods of the victims in more to those in targets
+func mergeVictimPods(targets []victimPods, more []victimPods) []victimPods {
+	for _, next := range more {
+		merged := false
+		for i := range targets {
+			if targets[i].victim == next.victim {
+				targets[i].pods = append(targets[i].pods, next.pods...)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			targets = append(targets, next)
+		}
+	}
+	return targets
+}
+
+// Keeps a random subset of the pods of each victim within its zone outage
+// cap, and returns the number of pods left out
+func capVictimPods(targets []victimPods, r *rand.Rand) (capped []victimPods, spared int) {
+	for _, target := range targets {
+		percent := config.ZoneOutageMaxPercent()
+		if limiter, ok := target.victim.(victims.VictimZoneOutageLimiter); ok {
+			percent = limiter.ZoneOutageMaxPercent()
+		}
+
+		max := target.running * percent / 100
+		if len(target.pods) > max {
+			glog.V(3).Infof("Terminating %d of %d pods of %s %s in the zone to respect its zone outage cap of %d%%", max, len(target.pods), target.victim.Kind(), target.victim.Name(), percent)
+			spared += len(target.pods) - max
+			pods := append(target.pods[:0:0], target.pods...)
+			r.Shuffle(len(pods), func(i, j int) { pods[i], pods[j] = pods[j], pods[i] })
+			target.pods = pods[:max]
+		}
+		if len(target.pods) > 0 {
+			capped = append(capped, target)
+		}
+	}
+	return capped, spared
+}
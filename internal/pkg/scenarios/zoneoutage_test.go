@@ -0,0 +1,84 @@
+package scenarios
+
+import (
+	"testing"
+
+	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/config/param"
+	"kube-monkey/internal/pkg/victims"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newNode(name, zone string) *corev1.Node {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if zone != "" {
+		node.Labels = map[string]string{config.ZoneLabelKey: zone}
+	}
+	return node
+}
+
+func TestZoneOutage(t *testing.T) {
+	defer viper.Set(param.ZoneOutageMaxPercent, viper.GetInt(param.ZoneOutageMaxPercent))
+	viper.Set(param.ZoneOutageMaxPercent, 100)
+
+	client := fake.NewSimpleClientset(
+		newNode("node-1", "zone-a"),
+		newNode("node-2", "zone-a"),
+		newNode("node-3", ""),
+		newPod("app1-a", "app1", "node-1"),
+		newPod("app1-b", "app1", "node-2"),
+		newPod("app1-c", "app1", "node-3"),
+		newPod("app2-a", "app2", "node-2"),
+	)
+	outage := NewZoneOutage(lister(newVictim(t, "app1"), newVictim(t, "app2")))
+
+	outcome, affected, err := outage.Run(client)
+	assert.NoError(t, err)
+	assert.Equal(t, "Zone zone-a went down", outcome)
+	assert.Equal(t, []victims.AffectedWorkload{
+		{Kind: "v1.Deployment", Namespace: NAMESPACE, Name: "app1", Pods: []string{"app1-a", "app1-b"}, Running: 3},
+		{Kind: "v1.Deployment", Namespace: NAMESPACE, Name: "app2", Pods: []string{"app2-a"}, Running: 1},
+	}, affected)
+	assert.Equal(t, []string{"app1-c"}, podNames(client), "Expected the pods on nodes without zone to be spared")
+}
+
+func TestZoneOutageCap(t *testing.T) {
+	defer viper.Set(param.ZoneOutageMaxPercent, viper.GetInt(param.ZoneOutageMaxPercent))
+	viper.Set(param.ZoneOutageMaxPercent, 50)
+
+	client := fake.NewSimpleClientset(
+		newNode("node-1", "zone-a"),
+		newPod("app1-a", "app1", "node-1"),
+		newPod("app1-b", "app1", "node-1"),
+		newPod("app1-c", "app1", "node-1"),
+		newPod("app1-d", "app1", "node-1"),
+		newPod("app2-a", "app2", "node-1"),
+	)
+	outage := NewZoneOutage(lister(newVictim(t, "app1"), newVictim(t, "app2")))
+
+	outcome, affected, err := outage.Run(client)
+	assert.NoError(t, err)
+	assert.Equal(t, "Zone zone-a went down, sparing 3 pods to respect the zone outage caps of their victims", outcome)
+	if assert.Len(t, affected, 1, "Expected app2 to be spared, since half of its single pod rounds down to none") {
+		assert.Equal(t, "app1", affected[0].Name)
+		assert.Len(t, affected[0].Pods, 2)
+		assert.Equal(t, 4, affected[0].Running)
+	}
+	assert.Len(t, podNames(client), 3)
+}
+
+func TestZoneOutageWithoutZones(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		newNode("node-1", ""),
+		newPod("app1-a", "app1", "node-1"),
+	)
+	outage := NewZoneOutage(lister(newVictim(t, "app1")))
+
+	_, _, err := outage.Run(client)
+	assert.EqualError(t, err, "no availability zone hosts running pods of eligible victims at the moment")
+}
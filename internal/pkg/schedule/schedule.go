@@ -12,6 +12,8 @@ import (
 	"kube-monkey/internal/pkg/calendar"
 	"kube-monkey/internal/pkg/chaos"
 	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/scenarios"
+	"kube-monkey/internal/pkg/victims"
 	"kube-monkey/internal/pkg/victims/factory"
 )
 
@@ -26,6 +28,10 @@ const (
 	End           = "\t********** End of schedule **********"
 )
 
+// MaxTerminationsPerDay bounds the terminations of a victim in a day, as
+// set by victims.MinMtbf
+const MaxTerminationsPerDay = int(24 * time.Hour / victims.MinMtbf)
+
 type Schedule struct {
 	entries []*chaos.Chaos
 }
@@ -38,6 +44,15 @@ func (s *Schedule) Add(entry *chaos.Chaos) {
 	s.entries = append(s.entries, entry)
 }
 
+// Schedules the terminations of the victim for today, spread over its
+// kill window
+func (s *Schedule) addTerminations(victim victims.Victim) {
+	terminations := TerminationsToday(victim.Mtbf())
+	for _, killtime := range CalculateKillTimes(victim, terminations) {
+		s.Add(chaos.New(killtime, victim))
+	}
+}
+
 func (s *Schedule) String() string {
 	schedString := []string{}
 
@@ -71,7 +86,7 @@ func (s Schedule) Print() {
 
 func New() (*Schedule, error) {
 	glog.V(3).Info("Status Update: Generating schedule for terminations")
-	victims, err := factory.EligibleVictims()
+	eligibleVictims, err := factory.EligibleVictims()
 	if err != nil {
 		return nil, err
 	}
@@ -80,29 +95,114 @@ func New() (*Schedule, error) {
 		entries: []*chaos.Chaos{},
 	}
 
-	for _, victim := range victims {
-		killtime := CalculateKillTime()
+	for _, victim := range eligibleVictims {
+		schedule.addTerminations(victim)
+	}
 
-		if ShouldScheduleChaos(victim.Mtbf()) {
-			schedule.Add(chaos.New(killtime, victim))
-		}
+	// Nodes are scheduled like workloads, but a failure to list them
+	// does not prevent the workloads from being attacked
+	eligibleNodes, err := factory.EligibleNodes()
+	if err != nil {
+		glog.Warningf("Failed to fetch eligible nodes due to error: %s", err.Error())
+	}
+	for _, node := range eligibleNodes {
+		schedule.addTerminations(node)
+	}
+
+	// Scenarios pick their victims at the time of the outage
+	if config.NodeOutageMtbf() > 0 {
+		schedule.addTerminations(scenarios.NewNodeOutage(factory.EligibleVictims))
+	}
+
+	if config.ZoneOutageMtbf() > 0 {
+		schedule.addTerminations(scenarios.NewZoneOutage(factory.EligibleVictims))
+	}
+
+	if config.NodeTaintMtbf() > 0 {
+		schedule.addTerminations(scenarios.NewNodeTaint(factory.EligibleTaintNodes))
 	}
 
 	return schedule, nil
 }
 
-func CalculateKillTime() time.Time {
-	loc := config.Timezone()
+// CalculateKillTime returns a random time within the kill window of the
+// victim, or the global window if the victim does not define one
+// It returns the zero time if the window of the victim is not reached
+// before the next run of the scheduler
+func CalculateKillTime(victim victims.Victim) time.Time {
+	killtimes := CalculateKillTimes(victim, 1)
+	if len(killtimes) == 0 {
+		return time.Time{}
+	}
+	return killtimes[0]
+}
+
+// CalculateKillTimes returns n random times within the kill window of the
+// victim, or the global window if the victim does not define one
+// The times are spread over the window, one in each of n equal slots
+// If the window of the victim is under way or over, the times are drawn
+// from what is left of it or from its next occurrence before the next run
+// of the scheduler, and none are returned if there is no such occurrence
+func CalculateKillTimes(victim victims.Victim, n int) []time.Time {
+	if n <= 0 {
+		return nil
+	}
+
+	startHour, endHour, loc := config.StartHour(), config.EndHour(), config.Timezone()
+	window := victim.KillWindow()
+	if window != nil {
+		startHour, endHour, loc = window.StartHour, window.EndHour, window.Location
+	}
+
 	if config.DebugEnabled() && config.DebugScheduleImmediateKill() {
 		r := rand.New(rand.NewSource(time.Now().UnixNano()))
-		// calculate a second-offset in the next minute
-		secOffset := r.Intn(60)
-		return time.Now().In(loc).Add(time.Duration(secOffset) * time.Second)
+		killtimes := make([]time.Time, n)
+		for i := range killtimes {
+			// calculate a second-offset in the next minute
+			secOffset := r.Intn(60)
+			killtimes[i] = time.Now().In(loc).Add(time.Duration(secOffset) * time.Second)
+		}
+		return killtimes
+	}
+
+	if window == nil {
+		return calendar.RandomTimesInRange(startHour, endHour, loc, n)
+	}
+
+	// The window of a victim in another time zone may already be under
+	// way or over when the schedule is built. Kills are then drawn from
+	// what is left of it, or from its next occurrence on a weekday, as long
+	// as they happen before the next run of the scheduler
+	now := time.Now()
+	nextRun := calendar.NextRuntime(config.Timezone(), config.RunHour())
+	windowStart, windowEnd, ok := calendar.NextWindow(now, nextRun, startHour, endHour, loc)
+	if !ok {
+		glog.Warningf("Kill window of %s %s/%s is not reached before the next schedule at %s, skipping it", victim.Kind(), victim.Namespace(), victim.Name(), nextRun)
+		return nil
+	}
+	return calendar.RandomTimesBetween(windowStart, windowEnd, n)
+}
+
+// TerminationsToday returns how many terminations to schedule today for
+// a victim with the given mean time between failures, in days
+// A victim with an mtbf shorter than a day gets a termination for every
+// whole mtbf in a day, and maybe one more for the remainder, up to
+// MaxTerminationsPerDay
+func TerminationsToday(mtbf float64) int {
+	perDay := 1 / mtbf
+	if perDay > float64(MaxTerminationsPerDay) {
+		return MaxTerminationsPerDay
+	}
+	terminations := int(perDay)
+
+	remainder := perDay - float64(terminations)
+	if remainder > 0 && ShouldScheduleChaos(1/remainder) {
+		terminations++
 	}
-	return calendar.RandomTimeInRange(config.StartHour(), config.EndHour(), loc)
+	return terminations
 }
 
-func ShouldScheduleChaos(mtbf int) bool {
+func ShouldScheduleChaos(mtbf float64) bool {
 	if config.DebugEnabled() && config.DebugForceShouldKill() {
 		return true
 	}
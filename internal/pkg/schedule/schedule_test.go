@@ -7,8 +7,10 @@ import (
 	"testing"
 	"time"
 
+	"kube-monkey/internal/pkg/calendar"
 	"kube-monkey/internal/pkg/chaos"
 	"kube-monkey/internal/pkg/config/param"
+	"kube-monkey/internal/pkg/victims"
 
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -87,7 +89,7 @@ func TestStringWithEntries(t *testing.T) {
 
 func TestCalculateKillTimeRandom(t *testing.T) {
 	config.SetDefaults()
-	killtime := CalculateKillTime()
+	killtime := CalculateKillTime(chaos.NewVictimMock())
 
 	scheduledTime := func() (success bool) {
 		if killtime.Hour() >= config.StartHour() && killtime.Hour() <= config.EndHour() {
@@ -105,13 +107,77 @@ func TestCalculateKillTimeNow(t *testing.T) {
 	config.SetDefaults()
 	viper.SetDefault(param.DebugEnabled, true)
 	viper.SetDefault(param.DebugScheduleImmediateKill, true)
-	killtime := CalculateKillTime()
+	killtime := CalculateKillTime(chaos.NewVictimMock())
 
 	assert.Equal(t, killtime.Location(), config.Timezone())
 	assert.WithinDuration(t, killtime, time.Now(), time.Second*time.Duration(60))
 	config.SetDefaults()
 }
 
+// assertInWindow asserts that the kill time lies between now and the next
+// run of the scheduler, on a weekday and within the hours of the window
+func assertInWindow(t *testing.T, window *victims.KillWindow, killtime time.Time) {
+	nextRun := calendar.NextRuntime(config.Timezone(), config.RunHour())
+	assert.Equal(t, window.Location, killtime.Location())
+	assert.False(t, killtime.Before(time.Now().Add(-time.Minute)), "Expected the kill time after now, got %s", killtime)
+	assert.True(t, killtime.Before(nextRun), "Expected the kill time before the next run at %s, got %s", nextRun, killtime)
+	assert.True(t, killtime.Weekday() != time.Saturday && killtime.Weekday() != time.Sunday, "Expected the kill time on a weekday, got %s", killtime)
+	assert.True(t, killtime.Hour() >= window.StartHour && killtime.Hour() < window.EndHour, "Expected the kill time within the window, got %s", killtime)
+}
+
+func TestCalculateKillTimeWindow(t *testing.T) {
+	config.SetDefaults()
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	window := &victims.KillWindow{StartHour: 0, EndHour: 23, Location: loc}
+	victim := chaos.NewVictimMock()
+	victim.SetKillWindow(window)
+
+	killtime := CalculateKillTime(victim)
+
+	if assert.False(t, killtime.IsZero(), "Expected a kill time before the next run") {
+		assertInWindow(t, window, killtime)
+	}
+}
+
+// noonZone returns a time zone in which it is currently about noon
+func noonZone() *time.Location {
+	return time.FixedZone("noon", (12-time.Now().UTC().Hour())*3600)
+}
+
+func TestCalculateKillTimesWindowUnderWay(t *testing.T) {
+	config.SetDefaults()
+	loc := noonZone()
+	window := &victims.KillWindow{StartHour: 10, EndHour: 16, Location: loc}
+	victim := chaos.NewVictimMock()
+	victim.SetKillWindow(window)
+
+	killtimes := CalculateKillTimes(victim, 3)
+
+	for _, killtime := range killtimes {
+		assertInWindow(t, window, killtime)
+	}
+	if now := time.Now().In(loc); now.Weekday() != time.Saturday && now.Weekday() != time.Sunday {
+		if assert.Len(t, killtimes, 3) {
+			for _, killtime := range killtimes {
+				assert.Equal(t, now.Day(), killtime.Day(), "Expected the kill time in what is left of today's window, got %s", killtime)
+			}
+		}
+	}
+}
+
+func TestCalculateKillTimesWindowPassed(t *testing.T) {
+	config.SetDefaults()
+	loc := noonZone()
+	window := &victims.KillWindow{StartHour: 8, EndHour: 10, Location: loc}
+	victim := chaos.NewVictimMock()
+	victim.SetKillWindow(window)
+
+	for _, killtime := range CalculateKillTimes(victim, 2) {
+		assertInWindow(t, window, killtime)
+		assert.NotEqual(t, time.Now().In(loc).Day(), killtime.Day(), "Expected the kill time in the next occurrence of the window, got %s", killtime)
+	}
+}
+
 func TestShouldScheduleChaosNow(t *testing.T) {
 	config.SetDefaults()
 	viper.SetDefault(param.DebugEnabled, true)
@@ -124,3 +190,13 @@ func TestShouldScheduleChaosMtbf(t *testing.T) {
 	assert.False(t, ShouldScheduleChaos(100000000000))
 	assert.True(t, ShouldScheduleChaos(1))
 }
+
+func TestTerminationsToday(t *testing.T) {
+	assert.Equal(t, 0, TerminationsToday(100000000000))
+	assert.Equal(t, 1, TerminationsToday(1))
+	assert.Equal(t, 4, TerminationsToday(0.25))
+	assert.Equal(t, MaxTerminationsPerDay, TerminationsToday(1e-15), "Expected terminations to be capped")
+
+	terminations := TerminationsToday(0.4)
+	assert.True(t, terminations == 2 || terminations == 3, "Expected 2 or 3 terminations for 2.5 per day, got %d", terminations)
+}
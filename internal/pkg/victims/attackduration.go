@@ -0,0 +1,57 @@
+package victims
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"kube-monkey/internal/pkg/config"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AttackDuration returns how long temporary attacks on the victim last
+// before they are reverted
+func (v *VictimBase) AttackDuration() time.Duration {
+	if v.attackDuration == nil {
+		return config.AttackDuration()
+	}
+	return *v.attackDuration
+}
+
+// ConfigureAttackDuration sets how long temporary attacks on the victim last
+// from the config.AttackDurationLabelKey setting of the workload, or the
+// default of its enrolled namespace ns. The value is either a number of
+// seconds or a duration such as "10m"
+func (v *VictimBase) ConfigureAttackDuration(obj metav1.Object, ns *corev1.Namespace) error {
+	value, ok := SettingOrDefault(obj, ns, config.AttackDurationLabelKey)
+	if !ok {
+		return nil
+	}
+
+	duration, err := ParseAttackDuration(value)
+	if err != nil {
+		return err
+	}
+	v.attackDuration = &duration
+	return nil
+}
+
+// ParseAttackDuration parses the value of the config.AttackDurationLabelKey
+// setting, either a number of seconds or a duration such as "10m"
+func ParseAttackDuration(value string) (time.Duration, error) {
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, secondsErr := strconv.Atoi(value)
+		if secondsErr != nil {
+			return 0, fmt.Errorf("Invalid value for label %s: %s", config.AttackDurationLabelKey, value)
+		}
+		duration = time.Duration(seconds) * time.Second
+	}
+
+	if !(duration > 0) {
+		return 0, fmt.Errorf("Invalid value for label %s: %s", config.AttackDurationLabelKey, value)
+	}
+	return duration, nil
+}
@@ -0,0 +1,186 @@
+package victims
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"kube-monkey/internal/pkg/config"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+
+	kube "k8s.io/client-go/kubernetes"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// How long and how often a container is watched for a restart by the
+// kubelet after it was signaled
+var (
+	containerRestartTimeout      = 2 * time.Minute
+	containerRestartPollInterval = 5 * time.Second
+)
+
+// PodExecutor runs a command in a container of a pod through the pods/exec
+// subresource, such as kubernetes.PodExecutor
+type PodExecutor interface {
+	Exec(namespace, pod, container string, command []string) error
+}
+
+// KillTarget returns the container signaled instead of deleting the pods of
+// the victim, or "" if whole pods are terminated
+func (v *VictimBase) KillTarget() string {
+	return v.killTarget
+}
+
+// KillSignal returns the signal sent to PID 1 of the targeted container,
+// without the SIG prefix
+func (v *VictimBase) KillSignal() string {
+	if v.killSignal == "" {
+		return config.KillSignal()
+	}
+	return v.killSignal
+}
+
+// ConfigureKillTarget sets the container to signal and the signal from the
+// config.KillTargetLabelKey and config.KillSignalLabelKey settings of the
+// workload, or the defaults of its enrolled namespace ns
+func (v *VictimBase) ConfigureKillTarget(obj metav1.Object, ns *corev1.Namespace) error {
+	if value, ok := SettingOrDefault(obj, ns, config.KillTargetLabelKey); ok {
+		container := strings.TrimPrefix(value, config.KillTargetContainerPrefix)
+		switch {
+		case value == config.KillTargetPod:
+			v.killTarget = ""
+		case container != value && container != "":
+			v.killTarget = container
+		default:
+			return fmt.Errorf("Invalid value for label %s: %s", config.KillTargetLabelKey, value)
+		}
+	}
+
+	if value, ok := SettingOrDefault(obj, ns, config.KillSignalLabelKey); ok {
+		if !config.IsValidKillSignal(value) {
+			return fmt.Errorf("Invalid value for label %s: %s", config.KillSignalLabelKey, value)
+		}
+		v.killSignal = value
+	}
+	return nil
+}
+
+// KillContainers sends the kill signal of the victim to PID 1 of its
+// targeted container in killNum of its running pods, picked like the pods
+// of a termination. The outcome tells for each pod whether the kubelet
+// restarted the container within containerRestartTimeout
+// The signal is sent with the kill command of the container, so containers
+// without one cannot be targeted. PID 1 ignores the signals it has no
+// handler for, which includes KILL when it is sent from within the container
+func (v *VictimBase) KillContainers(clientset kube.Interface, executor PodExecutor, killNum int) (string, error) {
+	container := v.KillTarget()
+	if container == "" {
+		return "", fmt.Errorf("%s %s does not have %s label or annotation targeting a container", v.kind, v.name, config.KillTargetLabelKey)
+	}
+
+	if killNum <= 0 {
+		return "", fmt.Errorf("no terminations requested for %s %s", v.kind, v.name)
+	}
+
+	pods, err := v.RunningPods(clientset)
+	if err != nil {
+		return "", err
+	}
+
+	var candidates []corev1.Pod
+	for _, pod := range pods {
+		if _, ok := containerRestarts(pod, container); ok {
+			candidates = append(candidates, pod)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("%s %s has no running pods with container %s at the moment", v.kind, v.name, container)
+	}
+
+	targets, err := v.SelectPods(clientset, candidates, killNum)
+	if err != nil {
+		return "", err
+	}
+
+	signal := v.KillSignal()
+	command := []string{"kill", "-s", signal, "1"}
+	if config.DryRun() {
+		for _, pod := range targets {
+			glog.Infof("[DryRun Mode] Sent SIG%s to container %s of pod %s for %s/%s", signal, container, pod.Name, v.namespace, v.name)
+		}
+		return fmt.Sprintf("Sent SIG%s to container %s of %d pods", signal, container, len(targets)), nil
+	}
+
+	// Signal all containers first, so that they restart at the same time
+	execErrs := make([]error, len(targets))
+	for i, pod := range targets {
+		glog.V(6).Infof("Sending SIG%s to container %s of pod %s for %s %s/%s", signal, container, pod.Name, v.kind, v.namespace, v.name)
+		execErrs[i] = executor.Exec(v.namespace, pod.Name, container, command)
+	}
+
+	var outcomes []string
+	var errs []error
+	for i, pod := range targets {
+		before, _ := containerRestarts(pod, container)
+		restarted, err := v.waitForContainerRestart(clientset, pod.Name, container, before)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "Failed to check restart of container %s of pod %s", container, pod.Name))
+			continue
+		}
+
+		// Killing PID 1 may cut the exec stream, which does not matter
+		// as long as the container restarted
+		if execErrs[i] != nil && !restarted {
+			errs = append(errs, errors.Wrapf(execErrs[i], "Failed to send SIG%s to container %s of pod %s", signal, container, pod.Name))
+			continue
+		}
+
+		outcome := fmt.Sprintf("Sent SIG%s to container %s of pod %s, which the kubelet restarted", signal, container, pod.Name)
+		if !restarted {
+			outcome = fmt.Sprintf("Sent SIG%s to container %s of pod %s, which was not restarted within %s", signal, container, pod.Name, containerRestartTimeout)
+		}
+		outcomes = append(outcomes, outcome)
+	}
+
+	return strings.Join(outcomes, "; "), utilerrors.NewAggregate(errs)
+}
+
+// Watches the container of the pod until its restart count exceeds before,
+// and reports whether it did within containerRestartTimeout
+func (v *VictimBase) waitForContainerRestart(clientset kube.Interface, podName, container string, before int32) (bool, error) {
+	err := wait.PollUntilContextTimeout(context.TODO(), containerRestartPollInterval, containerRestartTimeout, false, func(ctx context.Context) (bool, error) {
+		pod, err := clientset.CoreV1().Pods(v.namespace).Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		restarts, _ := containerRestarts(*pod, container)
+		return restarts > before, nil
+	})
+	if wait.Interrupted(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Returns the restarts of the container of the pod, and whether the pod
+// has that container
+func containerRestarts(pod corev1.Pod, container string) (int32, bool) {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == container {
+			return status.RestartCount, true
+		}
+	}
+	for _, spec := range pod.Spec.Containers {
+		if spec.Name == container {
+			return 0, true
+		}
+	}
+	return 0, false
+}
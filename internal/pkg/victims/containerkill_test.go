@@ -0,0 +1,118 @@
+package victims
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"kube-monkey/internal/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// Records the commands run in containers, and restarts the signaled
+// containers as the kubelet would if restart is set
+type fakePodExecutor struct {
+	client  *fake.Clientset
+	restart bool
+	err     error
+	execs   []string
+}
+
+func (f *fakePodExecutor) Exec(namespace, pod, container string, command []string) error {
+	f.execs = append(f.execs, pod+"/"+container+": "+strings.Join(command, " "))
+	if f.restart {
+		obj, _ := f.client.Tracker().Get(corev1.SchemeGroupVersion.WithResource("pods"), namespace, pod)
+		restarted := obj.(*corev1.Pod).DeepCopy()
+		restarted.Status.ContainerStatuses[0].RestartCount++
+		_ = f.client.Tracker().Update(corev1.SchemeGroupVersion.WithResource("pods"), restarted, namespace)
+	}
+	return f.err
+}
+
+func newContainerPod(name string) corev1.Pod {
+	pod := newPod(name, corev1.PodRunning)
+	pod.Spec.Containers = []corev1.Container{{Name: "app"}}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "app", RestartCount: 2}}
+	return pod
+}
+
+func newContainerKillVictim() *VictimBase {
+	v := newVictimBase()
+	v.killTarget = "app"
+	v.killSignal = "INT"
+	return v
+}
+
+func shortenContainerRestartTimeout() func() {
+	timeout, interval := containerRestartTimeout, containerRestartPollInterval
+	containerRestartTimeout, containerRestartPollInterval = 50*time.Millisecond, time.Millisecond
+	return func() {
+		containerRestartTimeout, containerRestartPollInterval = timeout, interval
+	}
+}
+
+func TestKillContainers(t *testing.T) {
+	defer shortenContainerRestartTimeout()()
+
+	pod := newContainerPod("app-1")
+	client := fake.NewSimpleClientset(&pod)
+	executor := &fakePodExecutor{client: client, restart: true, err: errors.New("stream closed")}
+
+	outcome, err := newContainerKillVictim().KillContainers(client, executor, 1)
+	assert.NoError(t, err, "Expected the exec error to be ignored, since the container restarted")
+	assert.Equal(t, "Sent SIGINT to container app of pod app-1, which the kubelet restarted", outcome)
+	assert.Equal(t, []string{"app-1/app: kill -s INT 1"}, executor.execs)
+	assert.Len(t, getPodList(client).Items, 1, "Expected the pod to be kept")
+}
+
+func TestKillContainersNotRestarted(t *testing.T) {
+	defer shortenContainerRestartTimeout()()
+
+	pod := newContainerPod("app-1")
+	client := fake.NewSimpleClientset(&pod)
+
+	outcome, err := newContainerKillVictim().KillContainers(client, &fakePodExecutor{client: client}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Sent SIGINT to container app of pod app-1, which was not restarted within 50ms", outcome)
+
+	_, err = newContainerKillVictim().KillContainers(client, &fakePodExecutor{client: client, err: errors.New("kill: not found")}, 1)
+	assert.EqualError(t, err, "Failed to send SIGINT to container app of pod app-1: kill: not found")
+}
+
+func TestKillContainersWithoutContainer(t *testing.T) {
+	pod := newPod("app-1", corev1.PodRunning)
+	client := fake.NewSimpleClientset(&pod)
+
+	_, err := newContainerKillVictim().KillContainers(client, &fakePodExecutor{client: client}, 1)
+	assert.EqualError(t, err, KIND+" "+NAME+" has no running pods with container app at the moment")
+}
+
+func TestConfigureKillTarget(t *testing.T) {
+	v := newVictimBase()
+	assert.Equal(t, "", v.KillTarget())
+	assert.Equal(t, config.KillSignal(), v.KillSignal())
+
+	pod := newPod("app", corev1.PodRunning)
+	pod.Labels[config.KillTargetLabelKey] = config.KillTargetContainerPrefix + "sidecar"
+	pod.Labels[config.KillSignalLabelKey] = "KILL"
+	assert.NoError(t, v.ConfigureKillTarget(&pod, nil))
+	assert.Equal(t, "sidecar", v.KillTarget())
+	assert.Equal(t, "KILL", v.KillSignal())
+
+	pod.Labels[config.KillTargetLabelKey] = config.KillTargetPod
+	assert.NoError(t, v.ConfigureKillTarget(&pod, nil))
+	assert.Equal(t, "", v.KillTarget())
+
+	pod.Labels[config.KillTargetLabelKey] = "container"
+	err := v.ConfigureKillTarget(&pod, nil)
+	assert.EqualError(t, err, "Invalid value for label "+config.KillTargetLabelKey+": container")
+
+	pod.Labels[config.KillTargetLabelKey] = config.KillTargetPod
+	pod.Labels[config.KillSignalLabelKey] = "SIGKILL"
+	err = v.ConfigureKillTarget(&pod, nil)
+	assert.EqualError(t, err, "Invalid value for label "+config.KillSignalLabelKey+": SIGKILL")
+}
@@ -0,0 +1,81 @@
+package victims
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"kube-monkey/internal/pkg/config"
+
+	kube "k8s.io/client-go/kubernetes"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BlockedByPDBError is returned when a PodDisruptionBudget refuses the
+// eviction of a pod
+type BlockedByPDBError struct {
+	Pod       string
+	Namespace string
+	Err       error
+}
+
+func (e *BlockedByPDBError) Error() string {
+	return fmt.Sprintf("eviction of pod %s/%s blocked by PodDisruptionBudget: %v", e.Namespace, e.Pod, e.Err)
+}
+
+func (e *BlockedByPDBError) Unwrap() error {
+	return e.Err
+}
+
+// IsBlockedByPDB checks if err, or any error it wraps, is a BlockedByPDBError
+func IsBlockedByPDB(err error) bool {
+	var blocked *BlockedByPDBError
+	return errors.As(err, &blocked)
+}
+
+// TerminationMode returns how the pods of the victim are terminated,
+// config.TerminationModeDelete or config.TerminationModeEvict
+func (v *VictimBase) TerminationMode() string {
+	if v.terminationMode == "" {
+		return config.TerminationMode()
+	}
+	return v.terminationMode
+}
+
+// ConfigureTerminationMode sets how the pods of the victim are terminated
+// from the config.TerminationModeLabelKey setting of the workload, or the
+// default of its enrolled namespace ns
+func (v *VictimBase) ConfigureTerminationMode(obj metav1.Object, ns *corev1.Namespace) error {
+	value, ok := SettingOrDefault(obj, ns, config.TerminationModeLabelKey)
+	if !ok {
+		return nil
+	}
+
+	if !config.IsValidTerminationMode(value) {
+		return fmt.Errorf("Invalid value for label %s: %s", config.TerminationModeLabelKey, value)
+	}
+	v.terminationMode = value
+	return nil
+}
+
+// EvictPod evicts the specified pod of the victim through the policy/v1
+// Eviction API, which honors PodDisruptionBudgets
+func (v *VictimBase) EvictPod(clientset kube.Interface, podName string) error {
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      podName,
+			Namespace: v.namespace,
+		},
+		DeleteOptions: v.GetDeleteOptsForPod(),
+	}
+
+	err := clientset.CoreV1().Pods(v.namespace).EvictV1(context.TODO(), eviction)
+	if apierrors.IsTooManyRequests(err) {
+		return &BlockedByPDBError{Pod: podName, Namespace: v.namespace, Err: err}
+	}
+	return err
+}
@@ -0,0 +1,74 @@
+package victims
+
+import (
+	"testing"
+
+	"kube-monkey/internal/pkg/config"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// Records the evicted pods, or refuses evictions as a PodDisruptionBudget would
+func evictionReactor(evicted *[]string, blocked bool) k8stesting.ReactionFunc {
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		if blocked {
+			return true, nil, apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+		}
+		eviction := action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction)
+		*evicted = append(*evicted, eviction.Name)
+		return true, nil, nil
+	}
+}
+
+func TestEvictPod(t *testing.T) {
+	v := newVictimBase()
+	v.terminationMode = config.TerminationModeEvict
+	pod := newPod("app", corev1.PodRunning)
+
+	var evicted []string
+	client := fake.NewSimpleClientset(&pod)
+	client.PrependReactor("create", "pods", evictionReactor(&evicted, false))
+
+	err := v.DeletePod(client, "app")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"app"}, evicted, "Expected the pod to be evicted instead of deleted")
+	assert.Len(t, getPodList(client).Items, 1)
+}
+
+func TestEvictPodBlockedByPDB(t *testing.T) {
+	v := newVictimBase()
+	v.terminationMode = config.TerminationModeEvict
+	pod := newPod("app", corev1.PodRunning)
+
+	client := fake.NewSimpleClientset(&pod)
+	client.PrependReactor("create", "pods", evictionReactor(nil, true))
+
+	err := v.DeleteRandomPods(client, 1)
+	assert.True(t, IsBlockedByPDB(err), "Expected a blocked eviction, got %v", err)
+	assert.True(t, IsBlockedByPDB(errors.Wrap(err, "wrapped")))
+	assert.False(t, IsBlockedByPDB(errors.New("other")))
+}
+
+func TestConfigureTerminationMode(t *testing.T) {
+	v := newVictimBase()
+	assert.Equal(t, config.TerminationMode(), v.TerminationMode())
+
+	pod := newPod("app", corev1.PodRunning)
+	pod.Labels[config.TerminationModeLabelKey] = config.TerminationModeEvict
+	assert.NoError(t, v.ConfigureTerminationMode(&pod, nil))
+	assert.Equal(t, config.TerminationModeEvict, v.TerminationMode())
+
+	pod.Labels[config.TerminationModeLabelKey] = "drain"
+	err := v.ConfigureTerminationMode(&pod, nil)
+	assert.EqualError(t, err, "Invalid value for label "+config.TerminationModeLabelKey+": drain")
+}
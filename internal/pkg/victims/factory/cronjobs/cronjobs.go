@@ -0,0 +1,47 @@
+package cronjobs
+
+import (
+	"fmt"
+
+	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/victims"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type CronJob struct {
+	*victims.VictimBase
+}
+
+// New creates a new instance of CronJob
+// ns is the namespace of the CronJob if it is enrolled as a whole, and nil otherwise
+// The pods of a CronJob are those of its currently active Jobs, so they are
+// looked up at the time of the termination and the identifier is optional
+func New(cj *batchv1.CronJob, ns *corev1.Namespace) (*CronJob, error) {
+	mtbf, err := meanTimeBetweenFailures(cj, ns)
+	if err != nil {
+		return nil, err
+	}
+	kind := fmt.Sprintf("%T", *cj)
+
+	ident, _ := victims.Setting(cj, config.IdentLabelKey)
+	victim := victims.New(kind, cj.Name, cj.Namespace, ident, mtbf)
+	victim.SetPodSelectorFunc(activeJobsSelector(victim))
+	if err := victim.ConfigureSettings(cj, ns); err != nil {
+		return nil, err
+	}
+
+	return &CronJob{VictimBase: victim}, nil
+}
+
+// Read the mean-time-between-failures value defined by the CronJob
+// in the setting defined by config.MtbfLabelKey, or the default of its namespace
+func meanTimeBetweenFailures(kubekind *batchv1.CronJob, ns *corev1.Namespace) (float64, error) {
+	mtbf, ok := victims.SettingOrDefault(kubekind, ns, config.MtbfLabelKey)
+	if !ok {
+		return -1, fmt.Errorf("%T %s does not have %s label or annotation", kubekind, kubekind.Name, config.MtbfLabelKey)
+	}
+
+	return victims.ParseMtbf(mtbf)
+}
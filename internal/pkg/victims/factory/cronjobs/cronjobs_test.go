@@ -0,0 +1,93 @@
+package cronjobs
+
+import (
+	"testing"
+
+	"kube-monkey/internal/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	IDENTIFIER = "kube-monkey-id"
+	NAME       = "cronjob_name"
+	NAMESPACE  = metav1.NamespaceDefault
+)
+
+func newCronJob(name string, labels map[string]string) batchv1.CronJob {
+
+	return batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: NAMESPACE,
+			Labels:    labels,
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+
+	v1cj := newCronJob(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: IDENTIFIER,
+			config.MtbfLabelKey:  "1",
+		},
+	)
+	cronjob, err := New(&v1cj, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.CronJob", cronjob.Kind())
+	assert.Equal(t, NAME, cronjob.Name())
+	assert.Equal(t, NAMESPACE, cronjob.Namespace())
+	assert.Equal(t, IDENTIFIER, cronjob.Identifier())
+	assert.Equal(t, float64(1), cronjob.Mtbf())
+}
+
+func TestNewWithoutIdentifier(t *testing.T) {
+	v1cj := newCronJob(
+		NAME,
+		map[string]string{
+			config.MtbfLabelKey: "1",
+		},
+	)
+	_, err := New(&v1cj, nil)
+
+	assert.NoError(t, err, "Expected the pods to be found through the active Jobs without "+config.IdentLabelKey)
+}
+
+func TestInvalidMtbf(t *testing.T) {
+	v1cj := newCronJob(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: IDENTIFIER,
+		},
+	)
+	_, err := New(&v1cj, nil)
+
+	assert.Errorf(t, err, "Expected an error if "+config.MtbfLabelKey+" label doesn't exist")
+
+	v1cj = newCronJob(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: IDENTIFIER,
+			config.MtbfLabelKey:  "string",
+		},
+	)
+	_, err = New(&v1cj, nil)
+
+	assert.Errorf(t, err, "Expected an error if "+config.MtbfLabelKey+" label can't be converted a Int type")
+
+	v1cj = newCronJob(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: IDENTIFIER,
+			config.MtbfLabelKey:  "0",
+		},
+	)
+	_, err = New(&v1cj, nil)
+
+	assert.Errorf(t, err, "Expected an error if "+config.MtbfLabelKey+" label is lower than 1")
+}
@@ -0,0 +1,158 @@
+package cronjobs
+
+//All these functions require api access specific to the version of the app
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang/glog"
+
+	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/victims"
+	"kube-monkey/internal/pkg/victims/factory/jobs"
+
+	kube "k8s.io/client-go/kubernetes"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+// EligibleCronJobs gets all eligible cronjobs that opted in (filtered by config.EnabledLabel)
+func EligibleCronJobs(clientset kube.Interface, namespace string, filter *metav1.ListOptions) (eligVictims []victims.Victim, err error) {
+	enabledVictims, err := clientset.BatchV1().CronJobs(namespace).List(context.TODO(), *filter)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := victims.NewEnrolledNamespaces(clientset)
+	for _, vic := range enabledVictims.Items {
+		ns, err := namespaces.Get(vic.Namespace)
+		if err != nil {
+			glog.Warningf("Skipping eligible %T %s because of error: %s", vic, vic.Name, err.Error())
+			continue
+		}
+
+		victim, err := New(&vic, ns)
+		if err != nil {
+			glog.Warningf("Skipping eligible %T %s because of error: %s", vic, vic.Name, err.Error())
+			continue
+		}
+
+		if victim.IsBlacklisted(clientset) {
+			continue
+		}
+
+		eligVictims = append(eligVictims, victim)
+	}
+
+	return
+}
+
+// Label set by the job controller on the pods of a Job. Recent versions of
+// Kubernetes also set batchv1.ControllerUidLabel, but all versions set this one
+const controllerUIDLabel = "controller-uid"
+
+// Returns a victims.PodSelectorFunc selecting the pods of the currently
+// active Jobs of the cronjob through their controller-uid label, so that
+// pods of finished or unrelated Jobs are left alone
+func activeJobsSelector(c *victims.VictimBase) victims.PodSelectorFunc {
+	return func(clientset kube.Interface) (labels.Selector, error) {
+		cronjob, err := clientset.BatchV1().CronJobs(c.Namespace()).Get(context.TODO(), c.Name(), metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if len(cronjob.Status.Active) == 0 {
+			return nil, fmt.Errorf("%s %s has no active Jobs", c.Kind(), c.Name())
+		}
+
+		var uids []string
+		for _, ref := range cronjob.Status.Active {
+			uids = append(uids, string(ref.UID))
+		}
+		req, err := labels.NewRequirement(controllerUIDLabel, selection.In, uids)
+		if err != nil {
+			return nil, err
+		}
+		return labels.NewSelector().Add(*req), nil
+	}
+}
+
+/* Below methods are used to verify the victim's attributes have not changed at the scheduled time of termination */
+
+// IsEnrolled checks if the cronjob is currently enrolled in kube-monkey
+func (c *CronJob) IsEnrolled(clientset kube.Interface) (bool, error) {
+	cronjob, err := clientset.BatchV1().CronJobs(c.Namespace()).Get(context.TODO(), c.Name(), metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+	return victims.IsEnrolled(clientset, cronjob)
+}
+
+// KillType returns current killtype config setting for update
+func (c *CronJob) KillType(clientset kube.Interface) (string, error) {
+	cronjob, err := clientset.BatchV1().CronJobs(c.Namespace()).Get(context.TODO(), c.Name(), metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	ns, err := victims.EnrolledNamespace(clientset, cronjob.Namespace)
+	if err != nil {
+		return "", err
+	}
+
+	killType, ok := victims.SettingOrDefault(cronjob, ns, config.KillTypeLabelKey)
+	if !ok {
+		return "", fmt.Errorf("%s %s does not have %s label or annotation", c.Kind(), c.Name(), config.KillTypeLabelKey)
+	}
+
+	return killType, nil
+}
+
+// KillValue returns current killvalue config setting for update
+func (c *CronJob) KillValue(clientset kube.Interface) (int, error) {
+	cronjob, err := clientset.BatchV1().CronJobs(c.Namespace()).Get(context.TODO(), c.Name(), metav1.GetOptions{})
+	if err != nil {
+		return -1, err
+	}
+	ns, err := victims.EnrolledNamespace(clientset, cronjob.Namespace)
+	if err != nil {
+		return -1, err
+	}
+
+	killMode, ok := victims.SettingOrDefault(cronjob, ns, config.KillValueLabelKey)
+	if !ok {
+		return -1, fmt.Errorf("%s %s does not have %s label or annotation", c.Kind(), c.Name(), config.KillValueLabelKey)
+	}
+
+	killModeInt, err := strconv.Atoi(killMode)
+	if err != nil || !(killModeInt > 0) {
+		return -1, fmt.Errorf("Invalid value for label %s: %d", config.KillValueLabelKey, killModeInt)
+	}
+
+	return killModeInt, nil
+}
+
+// WaitForCompletion waits for the currently active child Jobs of the cronjob
+// to complete or exhaust their backoffLimit
+// This blocks the termination for up to timeout, which is
+// config.JobCompletionTimeout, 30 minutes by default
+func (c *CronJob) WaitForCompletion(clientset kube.Interface, timeout time.Duration) (string, error) {
+	cronjob, err := clientset.BatchV1().CronJobs(c.Namespace()).Get(context.TODO(), c.Name(), metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	if len(cronjob.Status.Active) == 0 {
+		return fmt.Sprintf("%s %s has no active Jobs", c.Kind(), c.Name()), nil
+	}
+
+	var names []string
+	for _, ref := range cronjob.Status.Active {
+		names = append(names, ref.Name)
+	}
+
+	return jobs.WaitForJobs(clientset, c.Namespace(), names, timeout)
+}
@@ -0,0 +1,231 @@
+package cronjobs
+
+import (
+	"testing"
+	"time"
+
+	"kube-monkey/internal/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEligibleCronJobs(t *testing.T) {
+	v1cj := newCronJob(
+		NAME,
+		map[string]string{
+			"kube-monkey/identifier": "1",
+			"kube-monkey/mtbf":       "1",
+		},
+	)
+
+	client := fake.NewSimpleClientset(&v1cj)
+	victims, _ := EligibleCronJobs(client, NAMESPACE, &metav1.ListOptions{})
+
+	assert.Len(t, victims, 1)
+}
+
+func TestIsEnrolled(t *testing.T) {
+	v1cj := newCronJob(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey:   "1",
+			config.MtbfLabelKey:    "1",
+			config.EnabledLabelKey: config.EnabledLabelValue,
+		},
+	)
+
+	cronjob, _ := New(&v1cj, nil)
+
+	client := fake.NewSimpleClientset(&v1cj)
+
+	b, _ := cronjob.IsEnrolled(client)
+
+	assert.Equal(t, b, true, "Expected cronjob to be enrolled")
+}
+
+func TestIsNotEnrolled(t *testing.T) {
+	v1cj := newCronJob(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey:   "1",
+			config.MtbfLabelKey:    "1",
+			config.EnabledLabelKey: "x",
+		},
+	)
+
+	cronjob, _ := New(&v1cj, nil)
+
+	client := fake.NewSimpleClientset(&v1cj)
+
+	b, _ := cronjob.IsEnrolled(client)
+
+	assert.Equal(t, b, false, "Expected cronjob to not be enrolled")
+}
+
+func TestKillType(t *testing.T) {
+
+	ident := "1"
+	mtbf := "1"
+	killMode := "kill-mode"
+
+	v1cj := newCronJob(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: ident,
+			config.MtbfLabelKey:  mtbf,
+		},
+	)
+
+	cronjob, _ := New(&v1cj, nil)
+
+	client := fake.NewSimpleClientset(&v1cj)
+
+	_, err := cronjob.KillType(client)
+
+	assert.EqualError(t, err, cronjob.Kind()+" "+cronjob.Name()+" does not have "+config.KillTypeLabelKey+" label or annotation")
+
+	v1cj = newCronJob(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey:    ident,
+			config.MtbfLabelKey:     mtbf,
+			config.KillTypeLabelKey: killMode,
+		},
+	)
+
+	client = fake.NewSimpleClientset(&v1cj)
+
+	kill, _ := cronjob.KillType(client)
+
+	assert.Equal(t, kill, killMode, "Unexpected kill value, got %d", kill)
+}
+
+func TestKillValue(t *testing.T) {
+
+	ident := "1"
+	mtbf := "1"
+	killValue := "0"
+
+	v1cj := newCronJob(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: ident,
+			config.MtbfLabelKey:  mtbf,
+		},
+	)
+
+	cronjob, _ := New(&v1cj, nil)
+
+	client := fake.NewSimpleClientset(&v1cj)
+
+	_, err := cronjob.KillValue(client)
+
+	assert.EqualError(t, err, cronjob.Kind()+" "+cronjob.Name()+" does not have "+config.KillValueLabelKey+" label or annotation")
+
+	v1cj = newCronJob(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey:     ident,
+			config.MtbfLabelKey:      mtbf,
+			config.KillValueLabelKey: killValue,
+		},
+	)
+
+	client = fake.NewSimpleClientset(&v1cj)
+
+	_, err = cronjob.KillValue(client)
+
+	assert.EqualError(t, err, "Invalid value for label "+config.KillValueLabelKey+": "+killValue)
+
+	killValue = "1"
+
+	v1cj = newCronJob(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey:     ident,
+			config.MtbfLabelKey:      mtbf,
+			config.KillValueLabelKey: killValue,
+		},
+	)
+
+	client = fake.NewSimpleClientset(&v1cj)
+
+	kill, _ := cronjob.KillValue(client)
+
+	assert.Equalf(t, kill, 1, "Unexpected a kill value, got %d", kill)
+}
+
+func TestWaitForCompletion(t *testing.T) {
+	v1cj := newCronJob(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: "1",
+			config.MtbfLabelKey:  "1",
+		},
+	)
+
+	cronjob, _ := New(&v1cj, nil)
+	client := fake.NewSimpleClientset(&v1cj)
+
+	outcome, err := cronjob.WaitForCompletion(client, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, cronjob.Kind()+" "+NAME+" has no active Jobs", outcome)
+
+	v1cj.Status.Active = []corev1.ObjectReference{{Name: "job1"}}
+	v1job := batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "job1",
+			Namespace: NAMESPACE,
+		},
+		Status: batchv1.JobStatus{
+			Conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded"},
+			},
+		},
+	}
+	client = fake.NewSimpleClientset(&v1cj, &v1job)
+
+	outcome, err = cronjob.WaitForCompletion(client, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "Job job1 failed: BackoffLimitExceeded", outcome)
+}
+
+func newJobPod(name, jobUID string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: NAMESPACE,
+			Labels:    map[string]string{controllerUIDLabel: jobUID, config.IdentLabelKey: IDENTIFIER},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestRunningPods(t *testing.T) {
+	v1cj := newCronJob(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: IDENTIFIER,
+			config.MtbfLabelKey:  "1",
+		},
+	)
+	cronjob, _ := New(&v1cj, nil)
+
+	client := fake.NewSimpleClientset(&v1cj)
+	_, err := cronjob.RunningPods(client)
+	assert.EqualError(t, err, cronjob.Kind()+" "+NAME+" has no active Jobs")
+
+	// Pods of a finished Job share the identifier, but are left alone
+	v1cj.Status.Active = []corev1.ObjectReference{{Name: "job-2", UID: "uid-2"}}
+	client = fake.NewSimpleClientset(&v1cj, newJobPod("job-1-pod", "uid-1"), newJobPod("job-2-pod", "uid-2"))
+
+	pods, err := cronjob.RunningPods(client)
+	assert.NoError(t, err)
+	if assert.Len(t, pods, 1) {
+		assert.Equal(t, "job-2-pod", pods[0].Name)
+	}
+}
@@ -0,0 +1,102 @@
+package customresources
+
+import (
+	"fmt"
+
+	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/victims"
+
+	"k8s.io/client-go/dynamic"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// CustomResource is a victim of any resource configured in
+// config.CustomResources, accessed through the dynamic client
+type CustomResource struct {
+	*victims.VictimBase
+
+	client dynamic.Interface
+	gvr    schema.GroupVersionResource
+}
+
+// New creates a new instance of CustomResource
+// ns is the namespace of the resource if it is enrolled as a whole, and nil otherwise
+func New(client dynamic.Interface, gvr schema.GroupVersionResource, obj *unstructured.Unstructured, ns *corev1.Namespace) (*CustomResource, error) {
+	selector, err := podSelector(obj)
+	if err != nil {
+		return nil, err
+	}
+	mtbf, err := meanTimeBetweenFailures(obj, ns)
+	if err != nil {
+		return nil, err
+	}
+	kind := fmt.Sprintf("%s.%s", gvr.Version, obj.GetKind())
+
+	// The identifier label is optional, since pods are found with the selector
+	ident, _ := victims.Setting(obj, config.IdentLabelKey)
+	base := victims.New(kind, obj.GetName(), obj.GetNamespace(), ident, mtbf)
+	base.SetPodSelector(selector)
+	if config.VerifyPodOwners() {
+		base.SetPodOwner(obj.GetUID())
+	}
+	if err := base.ConfigureSettings(obj, ns); err != nil {
+		return nil, err
+	}
+
+	return &CustomResource{VictimBase: base, client: client, gvr: gvr}, nil
+}
+
+// Returns the selector for the pods of the resource from spec.selector
+// This is either a metav1.LabelSelector, as used by Argo Rollouts, or a
+// plain map of labels, as used by OpenShift DeploymentConfigs
+func podSelector(obj *unstructured.Unstructured) (labels.Selector, error) {
+	field, found, err := unstructured.NestedMap(obj.Object, "spec", "selector")
+	if err != nil {
+		return nil, err
+	}
+	if !found || len(field) == 0 {
+		return nil, fmt.Errorf("%s %s does not have spec.selector", obj.GetKind(), obj.GetName())
+	}
+
+	var selector labels.Selector
+	_, hasMatchLabels := field["matchLabels"]
+	_, hasMatchExpressions := field["matchExpressions"]
+	if hasMatchLabels || hasMatchExpressions {
+		labelSelector := &metav1.LabelSelector{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(field, labelSelector); err != nil {
+			return nil, err
+		}
+		if selector, err = metav1.LabelSelectorAsSelector(labelSelector); err != nil {
+			return nil, err
+		}
+	} else {
+		set, _, err := unstructured.NestedStringMap(obj.Object, "spec", "selector")
+		if err != nil {
+			return nil, err
+		}
+		selector = labels.SelectorFromSet(set)
+	}
+
+	// An empty selector would match every pod in the namespace
+	if selector.Empty() {
+		return nil, fmt.Errorf("%s %s has an empty selector", obj.GetKind(), obj.GetName())
+	}
+	return selector, nil
+}
+
+// Read the mean-time-between-failures value defined by the resource
+// in the setting defined by config.MtbfLabelKey, or the default of its namespace
+func meanTimeBetweenFailures(obj *unstructured.Unstructured, ns *corev1.Namespace) (float64, error) {
+	mtbf, ok := victims.SettingOrDefault(obj, ns, config.MtbfLabelKey)
+	if !ok {
+		return -1, fmt.Errorf("%s %s does not have %s label or annotation", obj.GetKind(), obj.GetName(), config.MtbfLabelKey)
+	}
+
+	return victims.ParseMtbf(mtbf)
+}
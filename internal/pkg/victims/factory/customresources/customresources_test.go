@@ -0,0 +1,104 @@
+package customresources
+
+import (
+	"testing"
+
+	"kube-monkey/internal/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+const (
+	IDENTIFIER = "kube-monkey-id"
+	NAME       = "rollout_name"
+	NAMESPACE  = metav1.NamespaceDefault
+)
+
+var rollouts = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "rollouts"}
+
+func newRollout(name string, labels map[string]string, selector map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "argoproj.io/v1alpha1",
+			"kind":       "Rollout",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": NAMESPACE,
+			},
+		},
+	}
+	obj.SetLabels(labels)
+	if selector != nil {
+		_ = unstructured.SetNestedMap(obj.Object, selector, "spec", "selector")
+	}
+	return obj
+}
+
+func newClient(objects ...runtime.Object) *fake.FakeDynamicClient {
+	return fake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{rollouts: "RolloutList"},
+		objects...,
+	)
+}
+
+func TestNew(t *testing.T) {
+
+	rollout := newRollout(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: IDENTIFIER,
+			config.MtbfLabelKey:  "1",
+		},
+		map[string]interface{}{
+			"matchLabels": map[string]interface{}{"app": "foo"},
+		},
+	)
+	cr, err := New(newClient(), rollouts, rollout, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "v1alpha1.Rollout", cr.Kind())
+	assert.Equal(t, NAME, cr.Name())
+	assert.Equal(t, NAMESPACE, cr.Namespace())
+	assert.Equal(t, IDENTIFIER, cr.Identifier())
+	assert.Equal(t, float64(1), cr.Mtbf())
+}
+
+func TestPodSelector(t *testing.T) {
+	rollout := newRollout(NAME, nil, map[string]interface{}{
+		"matchLabels": map[string]interface{}{"app": "foo"},
+		"matchExpressions": []interface{}{
+			map[string]interface{}{"key": "tier", "operator": "In", "values": []interface{}{"web"}},
+		},
+	})
+	selector, err := podSelector(rollout)
+	assert.NoError(t, err)
+	assert.Equal(t, "app=foo,tier in (web)", selector.String())
+
+	deploymentConfig := newRollout(NAME, nil, map[string]interface{}{"app": "foo"})
+	selector, err = podSelector(deploymentConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "app=foo", selector.String())
+
+	_, err = podSelector(newRollout(NAME, nil, nil))
+	assert.Errorf(t, err, "Expected an error if spec.selector doesn't exist")
+
+	empty := newRollout(NAME, nil, map[string]interface{}{"matchLabels": map[string]interface{}{}})
+	_, err = podSelector(empty)
+	assert.EqualError(t, err, "Rollout "+NAME+" has an empty selector", "Expected an empty selector to be rejected, as it matches every pod")
+}
+
+func TestInvalidMtbf(t *testing.T) {
+	selector := map[string]interface{}{"app": "foo"}
+
+	_, err := New(newClient(), rollouts, newRollout(NAME, map[string]string{}, selector), nil)
+	assert.Errorf(t, err, "Expected an error if "+config.MtbfLabelKey+" label doesn't exist")
+
+	_, err = New(newClient(), rollouts, newRollout(NAME, map[string]string{config.MtbfLabelKey: "0"}, selector), nil)
+	assert.Errorf(t, err, "Expected an error if "+config.MtbfLabelKey+" label is lower than 1")
+}
@@ -0,0 +1,112 @@
+package customresources
+
+//All these functions use the dynamic client, as the resources are not
+//known to the typed clientset. The clientset arguments required by
+//victims.Victim are only used to check namespaces
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/golang/glog"
+
+	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/victims"
+
+	"k8s.io/client-go/dynamic"
+	kube "k8s.io/client-go/kubernetes"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// EligibleCustomResources gets all eligible resources of the given type that opted in (filtered by config.EnabledLabel)
+func EligibleCustomResources(clientset kube.Interface, client dynamic.Interface, gvr schema.GroupVersionResource, namespace string, filter *metav1.ListOptions) (eligVictims []victims.Victim, err error) {
+	enabledVictims, err := client.Resource(gvr).Namespace(namespace).List(context.TODO(), *filter)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := victims.NewEnrolledNamespaces(clientset)
+	for _, vic := range enabledVictims.Items {
+		ns, err := namespaces.Get(vic.GetNamespace())
+		if err != nil {
+			glog.Warningf("Skipping eligible %s %s because of error: %s", vic.GetKind(), vic.GetName(), err.Error())
+			continue
+		}
+
+		victim, err := New(client, gvr, &vic, ns)
+		if err != nil {
+			glog.Warningf("Skipping eligible %s %s because of error: %s", vic.GetKind(), vic.GetName(), err.Error())
+			continue
+		}
+
+		if victim.IsBlacklisted(clientset) {
+			continue
+		}
+
+		eligVictims = append(eligVictims, victim)
+	}
+
+	return
+}
+
+func (c *CustomResource) get() (*unstructured.Unstructured, error) {
+	return c.client.Resource(c.gvr).Namespace(c.Namespace()).Get(context.TODO(), c.Name(), metav1.GetOptions{})
+}
+
+/* Below methods are used to verify the victim's attributes have not changed at the scheduled time of termination */
+
+// IsEnrolled checks if the resource is currently enrolled in kube-monkey
+func (c *CustomResource) IsEnrolled(clientset kube.Interface) (bool, error) {
+	obj, err := c.get()
+	if err != nil {
+		return false, err
+	}
+	return victims.IsEnrolled(clientset, obj)
+}
+
+// KillType returns current killtype config setting for update
+func (c *CustomResource) KillType(clientset kube.Interface) (string, error) {
+	obj, err := c.get()
+	if err != nil {
+		return "", err
+	}
+	ns, err := victims.EnrolledNamespace(clientset, obj.GetNamespace())
+	if err != nil {
+		return "", err
+	}
+
+	killType, ok := victims.SettingOrDefault(obj, ns, config.KillTypeLabelKey)
+	if !ok {
+		return "", fmt.Errorf("%s %s does not have %s label or annotation", c.Kind(), c.Name(), config.KillTypeLabelKey)
+	}
+
+	return killType, nil
+}
+
+// KillValue returns current killvalue config setting for update
+func (c *CustomResource) KillValue(clientset kube.Interface) (int, error) {
+	obj, err := c.get()
+	if err != nil {
+		return -1, err
+	}
+	ns, err := victims.EnrolledNamespace(clientset, obj.GetNamespace())
+	if err != nil {
+		return -1, err
+	}
+
+	killMode, ok := victims.SettingOrDefault(obj, ns, config.KillValueLabelKey)
+	if !ok {
+		return -1, fmt.Errorf("%s %s does not have %s label or annotation", c.Kind(), c.Name(), config.KillValueLabelKey)
+	}
+
+	killModeInt, err := strconv.Atoi(killMode)
+	if err != nil || !(killModeInt > 0) {
+		return -1, fmt.Errorf("Invalid value for label %s: %d", config.KillValueLabelKey, killModeInt)
+	}
+
+	return killModeInt, nil
+}
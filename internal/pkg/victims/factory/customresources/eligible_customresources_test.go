@@ -0,0 +1,129 @@
+package customresources
+
+import (
+	"testing"
+
+	"kube-monkey/internal/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var selector = map[string]interface{}{"app": "foo"}
+
+func TestEligibleCustomResources(t *testing.T) {
+	rollout := newRollout(
+		NAME,
+		map[string]string{
+			config.MtbfLabelKey: "1",
+		},
+		selector,
+	)
+
+	victims, err := EligibleCustomResources(fake.NewSimpleClientset(), newClient(rollout), rollouts, NAMESPACE, &metav1.ListOptions{})
+
+	assert.NoError(t, err)
+	assert.Len(t, victims, 1)
+}
+
+func TestIsEnrolled(t *testing.T) {
+	rollout := newRollout(
+		NAME,
+		map[string]string{
+			config.MtbfLabelKey:    "1",
+			config.EnabledLabelKey: config.EnabledLabelValue,
+		},
+		selector,
+	)
+	client := newClient(rollout)
+	cr, _ := New(client, rollouts, rollout, nil)
+
+	b, _ := cr.IsEnrolled(fake.NewSimpleClientset())
+
+	assert.Equal(t, b, true, "Expected rollout to be enrolled")
+}
+
+func TestIsEnrolledByNamespace(t *testing.T) {
+	rollout := newRollout(NAME, map[string]string{}, selector)
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        NAMESPACE,
+			Labels:      map[string]string{config.EnabledLabelKey: config.EnabledLabelValue},
+			Annotations: map[string]string{config.MtbfLabelKey: "2", config.KillTypeLabelKey: config.KillAllLabelValue},
+		},
+	}
+	clientset := fake.NewSimpleClientset(ns)
+
+	victims, err := EligibleCustomResources(clientset, newClient(rollout), rollouts, NAMESPACE, &metav1.ListOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, victims, 1) {
+		assert.Equal(t, float64(2), victims[0].Mtbf(), "Expected the mtbf default of the namespace")
+
+		b, _ := victims[0].IsEnrolled(clientset)
+		assert.True(t, b, "Expected rollout to be enrolled through its namespace")
+
+		kill, _ := victims[0].KillType(clientset)
+		assert.Equal(t, config.KillAllLabelValue, kill)
+	}
+}
+
+func TestKillType(t *testing.T) {
+	rollout := newRollout(
+		NAME,
+		map[string]string{
+			config.MtbfLabelKey: "1",
+		},
+		selector,
+	)
+	cr, _ := New(newClient(rollout), rollouts, rollout, nil)
+
+	_, err := cr.KillType(fake.NewSimpleClientset())
+
+	assert.EqualError(t, err, cr.Kind()+" "+cr.Name()+" does not have "+config.KillTypeLabelKey+" label or annotation")
+
+	rollout = newRollout(
+		NAME,
+		map[string]string{
+			config.MtbfLabelKey:     "1",
+			config.KillTypeLabelKey: config.KillAllLabelValue,
+		},
+		selector,
+	)
+	cr, _ = New(newClient(rollout), rollouts, rollout, nil)
+
+	kill, _ := cr.KillType(fake.NewSimpleClientset())
+
+	assert.Equal(t, config.KillAllLabelValue, kill)
+}
+
+func TestKillValue(t *testing.T) {
+	rollout := newRollout(
+		NAME,
+		map[string]string{
+			config.MtbfLabelKey:      "1",
+			config.KillValueLabelKey: "0",
+		},
+		selector,
+	)
+	cr, _ := New(newClient(rollout), rollouts, rollout, nil)
+
+	_, err := cr.KillValue(fake.NewSimpleClientset())
+
+	assert.EqualError(t, err, "Invalid value for label "+config.KillValueLabelKey+": 0")
+
+	rollout = newRollout(
+		NAME,
+		map[string]string{
+			config.MtbfLabelKey:      "1",
+			config.KillValueLabelKey: "2",
+		},
+		selector,
+	)
+	cr, _ = New(newClient(rollout), rollouts, rollout, nil)
+
+	kill, _ := cr.KillValue(fake.NewSimpleClientset())
+
+	assert.Equal(t, 2, kill)
+}
@@ -2,12 +2,12 @@ package daemonsets
 
 import (
 	"fmt"
-	"strconv"
 
 	"kube-monkey/internal/pkg/config"
 	"kube-monkey/internal/pkg/victims"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 )
 
 type DaemonSet struct {
@@ -15,49 +15,51 @@ type DaemonSet struct {
 }
 
 // New creates a new instance of DaemonSet
-func New(dep *appsv1.DaemonSet) (*DaemonSet, error) {
+// ns is the namespace of the DaemonSet if it is enrolled as a whole, and nil otherwise
+func New(dep *appsv1.DaemonSet, ns *corev1.Namespace) (*DaemonSet, error) {
 	ident, err := identifier(dep)
-	if err != nil {
+	// The identifier label is optional when pods are found by their selector,
+	// which is always the case for workloads enrolled through their namespace
+	if err != nil && !config.PodDiscoveryBySelector() && ns == nil {
 		return nil, err
 	}
-	mtbf, err := meanTimeBetweenFailures(dep)
+	mtbf, err := meanTimeBetweenFailures(dep, ns)
 	if err != nil {
 		return nil, err
 	}
 	kind := fmt.Sprintf("%T", *dep)
 
-	return &DaemonSet{VictimBase: victims.New(kind, dep.Name, dep.Namespace, ident, mtbf)}, nil
+	victim := victims.New(kind, dep.Name, dep.Namespace, ident, mtbf)
+	if err := victim.ConfigurePodDiscovery(dep.Spec.Selector, dep.UID); err != nil {
+		return nil, err
+	}
+	if err := victim.ConfigureSettings(dep, ns); err != nil {
+		return nil, err
+	}
+
+	return &DaemonSet{VictimBase: victim}, nil
 }
 
-// Returns the value of the label defined by config.IdentLabelKey
-// from the DaemonSet labels
+// Returns the value of the setting defined by config.IdentLabelKey
+// from the DaemonSet annotations or labels
 // This label should be unique to a DaemonSet, and is used to
 // identify the pods that belong to this DaemonSet, as pods
 // inherit labels from the DaemonSet
 func identifier(kubekind *appsv1.DaemonSet) (string, error) {
-	identifier, ok := kubekind.Labels[config.IdentLabelKey]
+	identifier, ok := victims.Setting(kubekind, config.IdentLabelKey)
 	if !ok {
-		return "", fmt.Errorf("%T %s does not have %s label", kubekind, kubekind.Name, config.IdentLabelKey)
+		return "", fmt.Errorf("%T %s does not have %s label or annotation", kubekind, kubekind.Name, config.IdentLabelKey)
 	}
 	return identifier, nil
 }
 
 // Read the mean-time-between-failures value defined by the DaemonSet
-// in the label defined by config.MtbfLabelKey
-func meanTimeBetweenFailures(kubekind *appsv1.DaemonSet) (int, error) {
-	mtbf, ok := kubekind.Labels[config.MtbfLabelKey]
+// in the setting defined by config.MtbfLabelKey, or the default of its namespace
+func meanTimeBetweenFailures(kubekind *appsv1.DaemonSet, ns *corev1.Namespace) (float64, error) {
+	mtbf, ok := victims.SettingOrDefault(kubekind, ns, config.MtbfLabelKey)
 	if !ok {
-		return -1, fmt.Errorf("%T %s does not have %s label", kubekind, kubekind.Name, config.MtbfLabelKey)
-	}
-
-	mtbfInt, err := strconv.Atoi(mtbf)
-	if err != nil {
-		return -1, err
-	}
-
-	if !(mtbfInt > 0) {
-		return -1, fmt.Errorf("Invalid value for label %s: %d", config.MtbfLabelKey, mtbfInt)
+		return -1, fmt.Errorf("%T %s does not have %s label or annotation", kubekind, kubekind.Name, config.MtbfLabelKey)
 	}
 
-	return mtbfInt, nil
+	return victims.ParseMtbf(mtbf)
 }
@@ -24,8 +24,15 @@ func EligibleDaemonSets(clientset kube.Interface, namespace string, filter *meta
 		return nil, err
 	}
 
+	namespaces := victims.NewEnrolledNamespaces(clientset)
 	for _, vic := range enabledVictims.Items {
-		victim, err := New(&vic)
+		ns, err := namespaces.Get(vic.Namespace)
+		if err != nil {
+			glog.Warningf("Skipping eligible %T %s because of error: %s", vic, vic.Name, err.Error())
+			continue
+		}
+
+		victim, err := New(&vic, ns)
 		if err != nil {
 			glog.Warningf("Skipping eligible %T %s because of error: %s", vic, vic.Name, err.Error())
 			continue
@@ -34,7 +41,7 @@ func EligibleDaemonSets(clientset kube.Interface, namespace string, filter *meta
 		// TODO: After generating whitelisting ns list, this will move to factory.
 		// IsBlacklisted will change to something like IsAllowedNamespace
 		// and will only be used to verify at time of scheduled execution
-		if victim.IsBlacklisted() {
+		if victim.IsBlacklisted(clientset) {
 			continue
 		}
 
@@ -52,34 +59,42 @@ func (d *DaemonSet) IsEnrolled(clientset kube.Interface) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	return daemonset.Labels[config.EnabledLabelKey] == config.EnabledLabelValue, nil
+	return victims.IsEnrolled(clientset, daemonset)
 }
 
-// KillType returns current killtype config label for update
+// KillType returns current killtype config setting for update
 func (d *DaemonSet) KillType(clientset kube.Interface) (string, error) {
 	daemonset, err := clientset.AppsV1().DaemonSets(d.Namespace()).Get(context.TODO(), d.Name(), metav1.GetOptions{})
 	if err != nil {
 		return "", err
 	}
+	ns, err := victims.EnrolledNamespace(clientset, daemonset.Namespace)
+	if err != nil {
+		return "", err
+	}
 
-	killType, ok := daemonset.Labels[config.KillTypeLabelKey]
+	killType, ok := victims.SettingOrDefault(daemonset, ns, config.KillTypeLabelKey)
 	if !ok {
-		return "", fmt.Errorf("%s %s does not have %s label", d.Kind(), d.Name(), config.KillTypeLabelKey)
+		return "", fmt.Errorf("%s %s does not have %s label or annotation", d.Kind(), d.Name(), config.KillTypeLabelKey)
 	}
 
 	return killType, nil
 }
 
-// KillValue returns current killvalue config label for update
+// KillValue returns current killvalue config setting for update
 func (d *DaemonSet) KillValue(clientset kube.Interface) (int, error) {
 	daemonset, err := clientset.AppsV1().DaemonSets(d.Namespace()).Get(context.TODO(), d.Name(), metav1.GetOptions{})
 	if err != nil {
 		return -1, err
 	}
+	ns, err := victims.EnrolledNamespace(clientset, daemonset.Namespace)
+	if err != nil {
+		return -1, err
+	}
 
-	killMode, ok := daemonset.Labels[config.KillValueLabelKey]
+	killMode, ok := victims.SettingOrDefault(daemonset, ns, config.KillValueLabelKey)
 	if !ok {
-		return -1, fmt.Errorf("%s %s does not have %s label", d.Kind(), d.Name(), config.KillValueLabelKey)
+		return -1, fmt.Errorf("%s %s does not have %s label or annotation", d.Kind(), d.Name(), config.KillValueLabelKey)
 	}
 
 	killModeInt, err := strconv.Atoi(killMode)
@@ -93,3 +108,12 @@ func (d *DaemonSet) KillValue(clientset kube.Interface) (int, error) {
 
 	return killModeInt, nil
 }
+
+// Replicas returns the ready and desired pods of the daemonset
+func (d *DaemonSet) Replicas(clientset kube.Interface) (int32, int32, error) {
+	daemonset, err := clientset.AppsV1().DaemonSets(d.Namespace()).Get(context.TODO(), d.Name(), metav1.GetOptions{})
+	if err != nil {
+		return 0, 0, err
+	}
+	return daemonset.Status.NumberReady, daemonset.Status.DesiredNumberScheduled, nil
+}
@@ -35,7 +35,7 @@ func TestIsEnrolled(t *testing.T) {
 		},
 	)
 
-	depl, _ := New(&v1ds)
+	depl, _ := New(&v1ds, nil)
 
 	client := fake.NewSimpleClientset(&v1ds)
 
@@ -54,7 +54,7 @@ func TestIsNotEnrolled(t *testing.T) {
 		},
 	)
 
-	ds, _ := New(&v1ds)
+	ds, _ := New(&v1ds, nil)
 
 	client := fake.NewSimpleClientset(&v1ds)
 
@@ -77,13 +77,13 @@ func TestKillType(t *testing.T) {
 		},
 	)
 
-	depl, _ := New(&v1ds)
+	depl, _ := New(&v1ds, nil)
 
 	client := fake.NewSimpleClientset(&v1ds)
 
 	_, err := depl.KillType(client)
 
-	assert.EqualError(t, err, depl.Kind()+" "+depl.Name()+" does not have "+config.KillTypeLabelKey+" label")
+	assert.EqualError(t, err, depl.Kind()+" "+depl.Name()+" does not have "+config.KillTypeLabelKey+" label or annotation")
 
 	v1ds = newDaemonSet(
 		NAME,
@@ -115,13 +115,13 @@ func TestKillValue(t *testing.T) {
 		},
 	)
 
-	depl, _ := New(&v1ds)
+	depl, _ := New(&v1ds, nil)
 
 	client := fake.NewSimpleClientset(&v1ds)
 
 	_, err := depl.KillValue(client)
 
-	assert.EqualError(t, err, depl.Kind()+" "+depl.Name()+" does not have "+config.KillValueLabelKey+" label")
+	assert.EqualError(t, err, depl.Kind()+" "+depl.Name()+" does not have "+config.KillValueLabelKey+" label or annotation")
 
 	v1ds = newDaemonSet(
 		NAME,
@@ -155,3 +155,52 @@ func TestKillValue(t *testing.T) {
 
 	assert.Equalf(t, kill, 1, "Unexpected a kill value, got %d", kill)
 }
+
+func TestSettingsFromAnnotations(t *testing.T) {
+	v1ds := newDaemonSet(
+		NAME,
+		map[string]string{
+			config.EnabledLabelKey:  config.EnabledLabelValue,
+			config.KillTypeLabelKey: config.KillFixedLabelValue,
+		},
+	)
+	v1ds.Annotations = map[string]string{
+		config.IdentLabelKey:     "1",
+		config.MtbfLabelKey:      "2",
+		config.KillTypeLabelKey:  config.KillFixedPercentageLabelValue,
+		config.KillValueLabelKey: "50",
+	}
+
+	ds, err := New(&v1ds, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", ds.Identifier())
+	assert.Equal(t, float64(2), ds.Mtbf())
+
+	client := fake.NewSimpleClientset(&v1ds)
+
+	killType, _ := ds.KillType(client)
+	assert.Equal(t, config.KillFixedPercentageLabelValue, killType, "Expected the annotation to take precedence over the label")
+
+	killValue, _ := ds.KillValue(client)
+	assert.Equal(t, 50, killValue)
+}
+
+func TestReplicas(t *testing.T) {
+	v1ds := newDaemonSet(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: "1",
+			config.MtbfLabelKey:  "1",
+		},
+	)
+	ds, _ := New(&v1ds, nil)
+
+	v1ds.Status.DesiredNumberScheduled = 4
+	v1ds.Status.NumberReady = 3
+	client := fake.NewSimpleClientset(&v1ds)
+
+	ready, desired, err := ds.Replicas(client)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), ready)
+	assert.Equal(t, int32(4), desired)
+}
@@ -4,7 +4,9 @@ import (
 	"testing"
 
 	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/config/param"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -36,14 +38,14 @@ func TestNew(t *testing.T) {
 			config.MtbfLabelKey:  "1",
 		},
 	)
-	depl, err := New(&v1depl)
+	depl, err := New(&v1depl, nil)
 
 	assert.NoError(t, err)
 	assert.Equal(t, "v1.Deployment", depl.Kind())
 	assert.Equal(t, NAME, depl.Name())
 	assert.Equal(t, NAMESPACE, depl.Namespace())
 	assert.Equal(t, IDENTIFIER, depl.Identifier())
-	assert.Equal(t, 1, depl.Mtbf())
+	assert.Equal(t, float64(1), depl.Mtbf())
 }
 
 func TestInvalidIdentifier(t *testing.T) {
@@ -53,7 +55,7 @@ func TestInvalidIdentifier(t *testing.T) {
 			config.MtbfLabelKey: "1",
 		},
 	)
-	_, err := New(&v1depl)
+	_, err := New(&v1depl, nil)
 
 	assert.Errorf(t, err, "Expected an error if "+config.IdentLabelKey+" label doesn't exist")
 }
@@ -65,7 +67,7 @@ func TestInvalidMtbf(t *testing.T) {
 			config.IdentLabelKey: IDENTIFIER,
 		},
 	)
-	_, err := New(&v1depl)
+	_, err := New(&v1depl, nil)
 
 	assert.Errorf(t, err, "Expected an error if "+config.MtbfLabelKey+" label doesn't exist")
 
@@ -76,7 +78,7 @@ func TestInvalidMtbf(t *testing.T) {
 			config.MtbfLabelKey:  "string",
 		},
 	)
-	_, err = New(&v1depl)
+	_, err = New(&v1depl, nil)
 
 	assert.Errorf(t, err, "Expected an error if "+config.MtbfLabelKey+" label can't be converted a Int type")
 
@@ -87,7 +89,55 @@ func TestInvalidMtbf(t *testing.T) {
 			config.MtbfLabelKey:  "0",
 		},
 	)
-	_, err = New(&v1depl)
+	_, err = New(&v1depl, nil)
 
 	assert.Errorf(t, err, "Expected an error if "+config.MtbfLabelKey+" label is lower than 1")
 }
+
+func TestNewWithSelectorDiscovery(t *testing.T) {
+	viper.Set(param.PodDiscovery, config.PodDiscoverySelector)
+	defer viper.Set(param.PodDiscovery, config.PodDiscoveryIdentifier)
+
+	v1depl := newDeployment(
+		NAME,
+		map[string]string{
+			config.MtbfLabelKey: "1",
+		},
+	)
+	_, err := New(&v1depl, nil)
+
+	assert.Errorf(t, err, "Expected an error if the deployment has no selector")
+
+	v1depl.Spec.Selector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{"app": "foo"},
+	}
+	depl, err := New(&v1depl, nil)
+
+	assert.NoError(t, err, "Expected the "+config.IdentLabelKey+" label to be optional")
+	assert.Equal(t, "", depl.Identifier())
+}
+
+func TestNewWithKillWindow(t *testing.T) {
+	config.SetDefaults()
+
+	v1depl := newDeployment(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey:     IDENTIFIER,
+			config.MtbfLabelKey:      "1",
+			config.StartHourLabelKey: "8",
+		},
+	)
+	v1depl.Annotations = map[string]string{
+		config.TimezoneLabelKey: "Europe/Berlin",
+	}
+	depl, err := New(&v1depl, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 8, depl.KillWindow().StartHour)
+	assert.Equal(t, "Europe/Berlin", depl.KillWindow().Location.String())
+
+	v1depl.Labels[config.StartHourLabelKey] = "17"
+	_, err = New(&v1depl, nil)
+	assert.Errorf(t, err, "Expected an error if "+config.StartHourLabelKey+" is not before "+config.EndHourLabelKey)
+}
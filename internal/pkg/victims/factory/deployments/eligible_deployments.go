@@ -8,6 +8,7 @@ import (
 	"strconv"
 
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
 
 	"kube-monkey/internal/pkg/config"
 	"kube-monkey/internal/pkg/victims"
@@ -15,6 +16,8 @@ import (
 	kube "k8s.io/client-go/kubernetes"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 )
 
 // EligibleDeployments gets all eligible deployments that opted in (filtered by config.EnabledLabel)
@@ -24,8 +27,15 @@ func EligibleDeployments(clientset kube.Interface, namespace string, filter *met
 		return nil, err
 	}
 
+	namespaces := victims.NewEnrolledNamespaces(clientset)
 	for _, vic := range enabledVictims.Items {
-		victim, err := New(&vic)
+		ns, err := namespaces.Get(vic.Namespace)
+		if err != nil {
+			glog.Warningf("Skipping eligible %T %s because of error: %s", vic, vic.Name, err.Error())
+			continue
+		}
+
+		victim, err := New(&vic, ns)
 		if err != nil {
 			glog.Warningf("Skipping eligible %T %s because of error: %s", vic, vic.Name, err.Error())
 			continue
@@ -34,7 +44,7 @@ func EligibleDeployments(clientset kube.Interface, namespace string, filter *met
 		// TODO: After generating whitelisting ns list, this will move to factory.
 		// IsBlacklisted will change to something like IsAllowedNamespace
 		// and will only be used to verify at time of scheduled execution
-		if victim.IsBlacklisted() {
+		if victim.IsBlacklisted(clientset) {
 			continue
 		}
 
@@ -52,34 +62,42 @@ func (d *Deployment) IsEnrolled(clientset kube.Interface) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	return deployment.Labels[config.EnabledLabelKey] == config.EnabledLabelValue, nil
+	return victims.IsEnrolled(clientset, deployment)
 }
 
-// KillType returns current killtype config label for update
+// KillType returns current killtype config setting for update
 func (d *Deployment) KillType(clientset kube.Interface) (string, error) {
 	deployment, err := clientset.AppsV1().Deployments(d.Namespace()).Get(context.TODO(), d.Name(), metav1.GetOptions{})
 	if err != nil {
 		return "", err
 	}
+	ns, err := victims.EnrolledNamespace(clientset, deployment.Namespace)
+	if err != nil {
+		return "", err
+	}
 
-	killType, ok := deployment.Labels[config.KillTypeLabelKey]
+	killType, ok := victims.SettingOrDefault(deployment, ns, config.KillTypeLabelKey)
 	if !ok {
-		return "", fmt.Errorf("%s %s does not have %s label", d.Kind(), d.Name(), config.KillTypeLabelKey)
+		return "", fmt.Errorf("%s %s does not have %s label or annotation", d.Kind(), d.Name(), config.KillTypeLabelKey)
 	}
 
 	return killType, nil
 }
 
-// KillValue returns current killvalue config label for update
+// KillValue returns current killvalue config setting for update
 func (d *Deployment) KillValue(clientset kube.Interface) (int, error) {
 	deployment, err := clientset.AppsV1().Deployments(d.Namespace()).Get(context.TODO(), d.Name(), metav1.GetOptions{})
 	if err != nil {
 		return -1, err
 	}
+	ns, err := victims.EnrolledNamespace(clientset, deployment.Namespace)
+	if err != nil {
+		return -1, err
+	}
 
-	killMode, ok := deployment.Labels[config.KillValueLabelKey]
+	killMode, ok := victims.SettingOrDefault(deployment, ns, config.KillValueLabelKey)
 	if !ok {
-		return -1, fmt.Errorf("%s %s does not have %s label", d.Kind(), d.Name(), config.KillValueLabelKey)
+		return -1, fmt.Errorf("%s %s does not have %s label or annotation", d.Kind(), d.Name(), config.KillValueLabelKey)
 	}
 
 	killModeInt, err := strconv.Atoi(killMode)
@@ -89,3 +107,58 @@ func (d *Deployment) KillValue(clientset kube.Interface) (int, error) {
 
 	return killModeInt, nil
 }
+
+// Replicas returns the ready and desired replicas of the deployment
+func (d *Deployment) Replicas(clientset kube.Interface) (int32, int32, error) {
+	deployment, err := clientset.AppsV1().Deployments(d.Namespace()).Get(context.TODO(), d.Name(), metav1.GetOptions{})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Replicas defaults to 1 if not specified
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+	return deployment.Status.ReadyReplicas, desired, nil
+}
+
+// ScaleDown scales the deployment down by replicas for its attack duration
+func (d *Deployment) ScaleDown(clientset kube.Interface, replicas int) (string, error) {
+	deployment, err := clientset.AppsV1().Deployments(d.Namespace()).Get(context.TODO(), d.Name(), metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	return d.ScaleDownWorkload(clientset.AppsV1().Deployments(d.Namespace()), annotationPatcher(clientset, d.Namespace()), deployment.Annotations, replicas)
+}
+
+// RestoreReplicas restores the original replicas of the deployments left
+// scaled down by attacks that were interrupted by a restart of kube-monkey
+// A deployment that fails to restore does not stop the others from being restored
+func RestoreReplicas(clientset kube.Interface) error {
+	deployments, err := clientset.AppsV1().Deployments(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, deployment := range deployments.Items {
+		restored, err := victims.RestoreReplicas(clientset.AppsV1().Deployments(deployment.Namespace), annotationPatcher(clientset, deployment.Namespace), deployment.Name, deployment.Annotations)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "Failed to restore replicas of Deployment %s/%s", deployment.Namespace, deployment.Name))
+			continue
+		}
+		if restored {
+			glog.V(3).Infof("Restored replicas of Deployment %s/%s left scaled down by an interrupted attack", deployment.Namespace, deployment.Name)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// Patches the annotations of the deployments in the namespace
+func annotationPatcher(clientset kube.Interface, namespace string) victims.AnnotationPatcher {
+	return func(ctx context.Context, name string, patch []byte) error {
+		_, err := clientset.AppsV1().Deployments(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+		return err
+	}
+}
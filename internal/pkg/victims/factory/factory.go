@@ -6,26 +6,38 @@ New types of kinds can be added easily
 package factory
 
 import (
+	"context"
+
 	"github.com/golang/glog"
 
 	"kube-monkey/internal/pkg/config"
 	"kube-monkey/internal/pkg/kubernetes"
 	"kube-monkey/internal/pkg/victims"
+	"kube-monkey/internal/pkg/victims/factory/cronjobs"
+	"kube-monkey/internal/pkg/victims/factory/customresources"
 	"kube-monkey/internal/pkg/victims/factory/daemonsets"
 	"kube-monkey/internal/pkg/victims/factory/deployments"
+	"kube-monkey/internal/pkg/victims/factory/jobs"
+	"kube-monkey/internal/pkg/victims/factory/nodes"
+	"kube-monkey/internal/pkg/victims/factory/pods"
+	"kube-monkey/internal/pkg/victims/factory/replicasets"
 	"kube-monkey/internal/pkg/victims/factory/statefulsets"
 
+	"k8s.io/client-go/dynamic"
+	kube "k8s.io/client-go/kubernetes"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 // EligibleVictims gathers list of enabled/enrolled kinds for judgement by
 // the scheduler
-// This checks against config.WhitelistedNamespaces but
+// This matches the namespaces of the apiserver against the whitelist,
+// including its patterns and config.WhitelistedNamespaceSelector, but
 // each victim checks themselves against the ns blacklist
-// TODO: fetch all namespaces from k8 apiserver to check blacklist here
 func EligibleVictims() (eligibleVictims []victims.Victim, err error) {
 	clientset, err := kubernetes.CreateClient()
 	if err != nil {
@@ -38,38 +50,181 @@ func EligibleVictims() (eligibleVictims []victims.Victim, err error) {
 		return nil, err
 	}
 
-	for _, namespace := range config.WhitelistedNamespaces().UnsortedList() {
-		// Fetch deployments
-		deployments, err := deployments.EligibleDeployments(clientset, namespace, filter)
+	// Custom resources are accessed through the dynamic client
+	customResources, err := config.CustomResources()
+	if err != nil {
+		return nil, err
+	}
+
+	var dynamicClient dynamic.Interface
+	if len(customResources) > 0 {
+		dynamicClient, err = kubernetes.CreateDynamicClient()
 		if err != nil {
-			//allow pass through to schedule other kinds and namespaces
-			glog.Warningf("Failed to fetch eligible deployments for namespace %s due to error: %s", namespace, err.Error())
+			return nil, err
+		}
+	}
+
+	namespaces, err := whitelistedNamespaces(clientset)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, namespace := range namespaces {
+		eligibleVictims = append(eligibleVictims, eligibleKinds(clientset, dynamicClient, labeledKinds, customResources, namespace, filter)...)
+	}
+
+	// Fetch workloads enrolled through their namespace
+	namespaceVictims, err := eligibleNamespaceVictims(clientset, dynamicClient, customResources)
+	if err != nil {
+		//allow pass through to schedule the victims enrolled by label
+		glog.Warningf("Failed to fetch eligible victims of enrolled namespaces due to error: %s", err.Error())
+	}
+	eligibleVictims = append(eligibleVictims, namespaceVictims...)
+
+	return
+}
+
+// EligibleNodes gathers the nodes that opted in to be drained through the
+// config.EnabledLabelKey label for judgement by the scheduler
+func EligibleNodes() ([]victims.Victim, error) {
+	return eligibleNodes(config.NodeAttackDrain)
+}
+
+// EligibleTaintNodes gathers the nodes that opted in to be tainted through
+// the config.EnabledLabelKey label and config.NodeAttackLabelKey
+func EligibleTaintNodes() ([]victims.Victim, error) {
+	return eligibleNodes(config.NodeAttackTaint)
+}
+
+func eligibleNodes(attack string) ([]victims.Victim, error) {
+	clientset, err := kubernetes.CreateClient()
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := enrollmentFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	return nodes.EligibleNodes(clientset, filter, attack)
+}
+
+// Gathers the workloads of namespaces that opted in as a whole
+// Workloads labeled with config.EnabledLabelKey are left out, as they
+// are either fetched already or opted out with another value
+// Bare pods are not workloads, and still have to opt in with the label
+func eligibleNamespaceVictims(clientset kube.Interface, dynamicClient dynamic.Interface, customResources []schema.GroupVersionResource) (eligibleVictims []victims.Victim, err error) {
+	namespaceFilter, err := enrollmentFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces, err := clientset.CoreV1().Namespaces().List(context.TODO(), *namespaceFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := unlabeledFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ns := range namespaces.Items {
+		namespace := ns.Name
+		whitelisted, err := victims.IsWhitelistedNamespace(&ns)
+		if err != nil {
+			return nil, err
+		}
+		if !whitelisted {
 			continue
 		}
-		eligibleVictims = append(eligibleVictims, deployments...)
 
-		// Fetch statefulsets
-		statefulsets, err := statefulsets.EligibleStatefulSets(clientset, namespace, filter)
+		eligibleVictims = append(eligibleVictims, eligibleKinds(clientset, dynamicClient, workloadKinds, customResources, namespace, filter)...)
+	}
+
+	return
+}
+
+// A kind of victims, and how to fetch the eligible ones of a namespace
+type eligibleKind struct {
+	name  string
+	fetch func(clientset kube.Interface, namespace string, filter *metav1.ListOptions) ([]victims.Victim, error)
+}
+
+// The kinds of workloads, which can also opt in through their namespace
+var workloadKinds = []eligibleKind{
+	{"deployments", deployments.EligibleDeployments},
+	{"statefulsets", statefulsets.EligibleStatefulSets},
+	{"daemonsets", daemonsets.EligibleDaemonSets},
+	{"replicasets", replicasets.EligibleReplicaSets},
+	{"jobs", jobs.EligibleJobs},
+	{"cronjobs", cronjobs.EligibleCronJobs},
+}
+
+// The kinds of victims that opt in through config.EnabledLabelKey, which
+// are the workloads and bare pods
+var labeledKinds = append(workloadKinds[:len(workloadKinds):len(workloadKinds)], eligibleKind{"pods", pods.EligiblePods})
+
+// Fetches the victims of the kinds and custom resources in the namespace
+// A kind that fails to be fetched, e.g. for lack of permissions, is logged
+// and skipped, so that the other kinds are still scheduled
+func eligibleKinds(clientset kube.Interface, dynamicClient dynamic.Interface, kinds []eligibleKind, customResources []schema.GroupVersionResource, namespace string, filter *metav1.ListOptions) (eligibleVictims []victims.Victim) {
+	for _, kind := range kinds {
+		kindVictims, err := kind.fetch(clientset, namespace, filter)
 		if err != nil {
 			//allow pass through to schedule other kinds and namespaces
-			glog.Warningf("Failed to fetch eligible statefulsets for namespace %s due to error: %s", namespace, err.Error())
+			glog.Warningf("Failed to fetch eligible %s for namespace %s due to error: %s", kind.name, namespace, err.Error())
 			continue
 		}
-		eligibleVictims = append(eligibleVictims, statefulsets...)
+		eligibleVictims = append(eligibleVictims, kindVictims...)
+	}
 
-		// Fetch daemonsets
-		daemonsets, err := daemonsets.EligibleDaemonSets(clientset, namespace, filter)
+	for _, gvr := range customResources {
+		resources, err := customresources.EligibleCustomResources(clientset, dynamicClient, gvr, namespace, filter)
 		if err != nil {
-			//allow pass through to schedule other kinds and namespaces
-			glog.Warningf("Failed to fetch eligible daemonsets for namespace %s due to error: %s", namespace, err.Error())
+			//allow pass through to schedule other resources and namespaces
+			glog.Warningf("Failed to fetch eligible %s for namespace %s due to error: %s", gvr.String(), namespace, err.Error())
 			continue
 		}
-		eligibleVictims = append(eligibleVictims, daemonsets...)
+		eligibleVictims = append(eligibleVictims, resources...)
 	}
 
 	return
 }
 
+// Lists the namespaces to fetch victims from. metav1.NamespaceAll
+// stands for all namespaces if no whitelist is configured
+// Otherwise all namespaces are matched against the whitelist, as it
+// can hold patterns and a selector
+func whitelistedNamespaces(clientset kube.Interface) ([]string, error) {
+	selector, err := config.WhitelistedNamespaceSelector()
+	if err != nil {
+		return nil, err
+	}
+	if !config.WhitelistEnabled() && selector == nil {
+		return []string{metav1.NamespaceAll}, nil
+	}
+
+	allNamespaces, err := clientset.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var namespaces []string
+	for _, ns := range allNamespaces.Items {
+		whitelisted, err := victims.IsWhitelistedNamespace(&ns)
+		if err != nil {
+			return nil, err
+		}
+		if whitelisted {
+			namespaces = append(namespaces, ns.Name)
+		}
+	}
+
+	return namespaces, nil
+}
+
 // Verifies opt-in of victims
 func enrollmentFilter() (*metav1.ListOptions, error) {
 	req, err := enrollmentRequirement()
@@ -84,3 +239,14 @@ func enrollmentFilter() (*metav1.ListOptions, error) {
 func enrollmentRequirement() (*labels.Requirement, error) {
 	return labels.NewRequirement(config.EnabledLabelKey, selection.Equals, sets.NewString(config.EnabledLabelValue).UnsortedList())
 }
+
+// Filters for victims without the enrollment label
+func unlabeledFilter() (*metav1.ListOptions, error) {
+	req, err := labels.NewRequirement(config.EnabledLabelKey, selection.DoesNotExist, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &metav1.ListOptions{
+		LabelSelector: labels.NewSelector().Add(*req).String(),
+	}, nil
+}
@@ -0,0 +1,189 @@
+package factory
+
+import (
+	"testing"
+
+	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/config/param"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newNamespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+			Annotations: map[string]string{
+				config.MtbfLabelKey: "2",
+			},
+		},
+	}
+}
+
+func newDeployment(name, namespace string, labels map[string]string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": name},
+			},
+		},
+	}
+}
+
+func newReplicaSet(name, namespace string) *appsv1.ReplicaSet {
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: appsv1.ReplicaSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": name},
+			},
+		},
+	}
+}
+
+func newJob(name, namespace string) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: batchv1.JobSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": name},
+			},
+		},
+	}
+}
+
+func newCronJob(name, namespace string) *batchv1.CronJob {
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{config.IdentLabelKey: name},
+		},
+	}
+}
+
+func TestEligibleNamespaceVictims(t *testing.T) {
+	config.SetDefaults()
+
+	client := fake.NewSimpleClientset(
+		newNamespace("enrolled", map[string]string{config.EnabledLabelKey: config.EnabledLabelValue}),
+		newNamespace("other", nil),
+		newDeployment("unlabeled", "enrolled", nil),
+		newDeployment("opted-out", "enrolled", map[string]string{config.EnabledLabelKey: "disabled"}),
+		newDeployment("labeled", "enrolled", map[string]string{config.EnabledLabelKey: config.EnabledLabelValue}),
+		newDeployment("not-enrolled", "other", nil),
+	)
+
+	victims, err := eligibleNamespaceVictims(client, nil, nil)
+
+	assert.NoError(t, err)
+	assert.Len(t, victims, 1)
+	assert.Equal(t, "unlabeled", victims[0].Name())
+	assert.Equal(t, float64(2), victims[0].Mtbf(), "Expected the mtbf default of the namespace")
+}
+
+func TestEligibleNamespaceVictimsOfEveryKind(t *testing.T) {
+	config.SetDefaults()
+
+	client := fake.NewSimpleClientset(
+		newNamespace("enrolled", map[string]string{config.EnabledLabelKey: config.EnabledLabelValue}),
+		newNamespace("other", nil),
+		newReplicaSet("replicaset", "enrolled"),
+		newJob("job", "enrolled"),
+		newCronJob("cronjob", "enrolled"),
+		newJob("not-enrolled", "other"),
+	)
+
+	victims, err := eligibleNamespaceVictims(client, nil, nil)
+
+	assert.NoError(t, err)
+	var names []string
+	for _, victim := range victims {
+		names = append(names, victim.Kind()+" "+victim.Name())
+		assert.Equal(t, float64(2), victim.Mtbf(), "Expected the mtbf default of the namespace")
+	}
+	assert.ElementsMatch(t, []string{"v1.ReplicaSet replicaset", "v1.Job job", "v1.CronJob cronjob"}, names)
+}
+
+func TestEligibleNamespaceVictimsSkipsFailedKind(t *testing.T) {
+	config.SetDefaults()
+
+	client := fake.NewSimpleClientset(
+		newNamespace("enrolled", map[string]string{config.EnabledLabelKey: config.EnabledLabelValue}),
+		newReplicaSet("replicaset", "enrolled"),
+		newJob("job", "enrolled"),
+		newCronJob("cronjob", "enrolled"),
+	)
+	client.PrependReactor("list", "replicasets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(appsv1.Resource("replicasets"), "", nil)
+	})
+
+	victims, err := eligibleNamespaceVictims(client, nil, nil)
+
+	assert.NoError(t, err)
+	var names []string
+	for _, victim := range victims {
+		names = append(names, victim.Name())
+	}
+	assert.ElementsMatch(t, []string{"job", "cronjob"}, names, "Expected the kinds after the failed one to be fetched")
+}
+
+func TestLabeledKinds(t *testing.T) {
+	var names []string
+	for _, kind := range labeledKinds {
+		names = append(names, kind.name)
+	}
+	assert.Equal(t, []string{"deployments", "statefulsets", "daemonsets", "replicasets", "jobs", "cronjobs", "pods"}, names)
+	assert.Len(t, workloadKinds, 6, "Expected bare pods to not opt in through their namespace")
+}
+
+func TestWhitelistedNamespaces(t *testing.T) {
+	config.SetDefaults()
+
+	client := fake.NewSimpleClientset(
+		newNamespace("staging", map[string]string{"env": "staging"}),
+		newNamespace("production", map[string]string{"env": "production"}),
+		newNamespace("team-a-staging", nil),
+		newNamespace("default", nil),
+	)
+
+	namespaces, err := whitelistedNamespaces(client)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{metav1.NamespaceAll}, namespaces)
+
+	viper.Set(param.WhitelistedNamespaceSelector, "env in (staging)")
+	defer viper.Set(param.WhitelistedNamespaceSelector, "")
+
+	namespaces, err = whitelistedNamespaces(client)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"staging"}, namespaces)
+
+	viper.Set(param.WhitelistedNamespaces, []string{"default"})
+	defer viper.Set(param.WhitelistedNamespaces, []string{metav1.NamespaceAll})
+
+	namespaces, err = whitelistedNamespaces(client)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"default", "staging"}, namespaces)
+
+	viper.Set(param.WhitelistedNamespaceSelector, "")
+	viper.Set(param.WhitelistedNamespaces, []string{"team-*-staging", "/prod.+/"})
+
+	namespaces, err = whitelistedNamespaces(client)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"team-a-staging", "production"}, namespaces)
+}
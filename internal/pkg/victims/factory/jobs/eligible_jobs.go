@@ -0,0 +1,173 @@
+package jobs
+
+//All these functions require api access specific to the version of the app
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+
+	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/victims"
+
+	kube "k8s.io/client-go/kubernetes"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Interval between checks of the Job status while waiting for completion
+const completionPollInterval = 10 * time.Second
+
+// EligibleJobs gets all eligible jobs that opted in (filtered by config.EnabledLabel)
+// Jobs that already finished are skipped, as are Jobs created by a CronJob
+// which are terminated through their CronJob instead
+func EligibleJobs(clientset kube.Interface, namespace string, filter *metav1.ListOptions) (eligVictims []victims.Victim, err error) {
+	enabledVictims, err := clientset.BatchV1().Jobs(namespace).List(context.TODO(), *filter)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := victims.NewEnrolledNamespaces(clientset)
+	for _, vic := range enabledVictims.Items {
+		if owner := metav1.GetControllerOf(&vic); owner != nil && owner.Kind == "CronJob" {
+			continue
+		}
+
+		if _, finished := Outcome(&vic); finished {
+			glog.V(5).Infof("Skipping eligible %T %s because it already finished", vic, vic.Name)
+			continue
+		}
+
+		ns, err := namespaces.Get(vic.Namespace)
+		if err != nil {
+			glog.Warningf("Skipping eligible %T %s because of error: %s", vic, vic.Name, err.Error())
+			continue
+		}
+
+		victim, err := New(&vic, ns)
+		if err != nil {
+			glog.Warningf("Skipping eligible %T %s because of error: %s", vic, vic.Name, err.Error())
+			continue
+		}
+
+		if victim.IsBlacklisted(clientset) {
+			continue
+		}
+
+		eligVictims = append(eligVictims, victim)
+	}
+
+	return
+}
+
+// Outcome returns a description of how the job finished, and whether it
+// finished at all. A failed job reports the reason, e.g. BackoffLimitExceeded
+func Outcome(job *batchv1.Job) (string, bool) {
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			return fmt.Sprintf("Job %s completed", job.Name), true
+		case batchv1.JobFailed:
+			return fmt.Sprintf("Job %s failed: %s", job.Name, cond.Reason), true
+		}
+	}
+	return fmt.Sprintf("Job %s has not finished", job.Name), false
+}
+
+// WaitForJobs polls the named jobs until all of them finished or the timeout
+// passed, and returns the outcome of each job
+func WaitForJobs(clientset kube.Interface, namespace string, names []string, timeout time.Duration) (string, error) {
+	outcomes := make([]string, len(names))
+
+	err := wait.PollUntilContextTimeout(context.TODO(), completionPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		done := true
+		for i, name := range names {
+			job, err := clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+
+			var finished bool
+			outcomes[i], finished = Outcome(job)
+			done = done && finished
+		}
+		return done, nil
+	})
+	if err != nil && !wait.Interrupted(err) {
+		return "", err
+	}
+
+	// On timeout the outcomes still describe the jobs that have not finished
+	return strings.Join(outcomes, "; "), nil
+}
+
+/* Below methods are used to verify the victim's attributes have not changed at the scheduled time of termination */
+
+// IsEnrolled checks if the job is currently enrolled in kube-monkey
+func (j *Job) IsEnrolled(clientset kube.Interface) (bool, error) {
+	job, err := clientset.BatchV1().Jobs(j.Namespace()).Get(context.TODO(), j.Name(), metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+	return victims.IsEnrolled(clientset, job)
+}
+
+// KillType returns current killtype config setting for update
+func (j *Job) KillType(clientset kube.Interface) (string, error) {
+	job, err := clientset.BatchV1().Jobs(j.Namespace()).Get(context.TODO(), j.Name(), metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	ns, err := victims.EnrolledNamespace(clientset, job.Namespace)
+	if err != nil {
+		return "", err
+	}
+
+	killType, ok := victims.SettingOrDefault(job, ns, config.KillTypeLabelKey)
+	if !ok {
+		return "", fmt.Errorf("%s %s does not have %s label or annotation", j.Kind(), j.Name(), config.KillTypeLabelKey)
+	}
+
+	return killType, nil
+}
+
+// KillValue returns current killvalue config setting for update
+func (j *Job) KillValue(clientset kube.Interface) (int, error) {
+	job, err := clientset.BatchV1().Jobs(j.Namespace()).Get(context.TODO(), j.Name(), metav1.GetOptions{})
+	if err != nil {
+		return -1, err
+	}
+	ns, err := victims.EnrolledNamespace(clientset, job.Namespace)
+	if err != nil {
+		return -1, err
+	}
+
+	killMode, ok := victims.SettingOrDefault(job, ns, config.KillValueLabelKey)
+	if !ok {
+		return -1, fmt.Errorf("%s %s does not have %s label or annotation", j.Kind(), j.Name(), config.KillValueLabelKey)
+	}
+
+	killModeInt, err := strconv.Atoi(killMode)
+	if err != nil || !(killModeInt > 0) {
+		return -1, fmt.Errorf("Invalid value for label %s: %d", config.KillValueLabelKey, killModeInt)
+	}
+
+	return killModeInt, nil
+}
+
+// WaitForCompletion waits for the job to complete or exhaust its backoffLimit
+// This blocks the termination for up to timeout, which is
+// config.JobCompletionTimeout, 30 minutes by default
+func (j *Job) WaitForCompletion(clientset kube.Interface, timeout time.Duration) (string, error) {
+	return WaitForJobs(clientset, j.Namespace(), []string{j.Name()}, timeout)
+}
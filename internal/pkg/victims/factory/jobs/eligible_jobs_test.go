@@ -0,0 +1,247 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"kube-monkey/internal/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEligibleJobs(t *testing.T) {
+	v1job := newJob(
+		NAME,
+		map[string]string{
+			"kube-monkey/identifier": "1",
+			"kube-monkey/mtbf":       "1",
+		},
+	)
+
+	client := fake.NewSimpleClientset(&v1job)
+	victims, _ := EligibleJobs(client, NAMESPACE, &metav1.ListOptions{})
+
+	assert.Len(t, victims, 1)
+}
+
+func TestIsEnrolled(t *testing.T) {
+	v1job := newJob(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey:   "1",
+			config.MtbfLabelKey:    "1",
+			config.EnabledLabelKey: config.EnabledLabelValue,
+		},
+	)
+
+	job, _ := New(&v1job, nil)
+
+	client := fake.NewSimpleClientset(&v1job)
+
+	b, _ := job.IsEnrolled(client)
+
+	assert.Equal(t, b, true, "Expected job to be enrolled")
+}
+
+func TestIsNotEnrolled(t *testing.T) {
+	v1job := newJob(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey:   "1",
+			config.MtbfLabelKey:    "1",
+			config.EnabledLabelKey: "x",
+		},
+	)
+
+	job, _ := New(&v1job, nil)
+
+	client := fake.NewSimpleClientset(&v1job)
+
+	b, _ := job.IsEnrolled(client)
+
+	assert.Equal(t, b, false, "Expected job to not be enrolled")
+}
+
+func TestKillType(t *testing.T) {
+
+	ident := "1"
+	mtbf := "1"
+	killMode := "kill-mode"
+
+	v1job := newJob(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: ident,
+			config.MtbfLabelKey:  mtbf,
+		},
+	)
+
+	job, _ := New(&v1job, nil)
+
+	client := fake.NewSimpleClientset(&v1job)
+
+	_, err := job.KillType(client)
+
+	assert.EqualError(t, err, job.Kind()+" "+job.Name()+" does not have "+config.KillTypeLabelKey+" label or annotation")
+
+	v1job = newJob(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey:    ident,
+			config.MtbfLabelKey:     mtbf,
+			config.KillTypeLabelKey: killMode,
+		},
+	)
+
+	client = fake.NewSimpleClientset(&v1job)
+
+	kill, _ := job.KillType(client)
+
+	assert.Equal(t, kill, killMode, "Unexpected kill value, got %d", kill)
+}
+
+func TestKillValue(t *testing.T) {
+
+	ident := "1"
+	mtbf := "1"
+	killValue := "0"
+
+	v1job := newJob(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: ident,
+			config.MtbfLabelKey:  mtbf,
+		},
+	)
+
+	job, _ := New(&v1job, nil)
+
+	client := fake.NewSimpleClientset(&v1job)
+
+	_, err := job.KillValue(client)
+
+	assert.EqualError(t, err, job.Kind()+" "+job.Name()+" does not have "+config.KillValueLabelKey+" label or annotation")
+
+	v1job = newJob(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey:     ident,
+			config.MtbfLabelKey:      mtbf,
+			config.KillValueLabelKey: killValue,
+		},
+	)
+
+	client = fake.NewSimpleClientset(&v1job)
+
+	_, err = job.KillValue(client)
+
+	assert.EqualError(t, err, "Invalid value for label "+config.KillValueLabelKey+": "+killValue)
+
+	killValue = "1"
+
+	v1job = newJob(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey:     ident,
+			config.MtbfLabelKey:      mtbf,
+			config.KillValueLabelKey: killValue,
+		},
+	)
+
+	client = fake.NewSimpleClientset(&v1job)
+
+	kill, _ := job.KillValue(client)
+
+	assert.Equalf(t, kill, 1, "Unexpected a kill value, got %d", kill)
+}
+
+func TestEligibleJobsSkipsCronJobOwned(t *testing.T) {
+	v1job := newJob(
+		NAME,
+		map[string]string{
+			"kube-monkey/identifier": "1",
+			"kube-monkey/mtbf":       "1",
+		},
+	)
+	isController := true
+	v1job.OwnerReferences = []metav1.OwnerReference{
+		{
+			APIVersion: "batch/v1",
+			Kind:       "CronJob",
+			Name:       "cronjob_name",
+			Controller: &isController,
+		},
+	}
+
+	client := fake.NewSimpleClientset(&v1job)
+	victims, _ := EligibleJobs(client, NAMESPACE, &metav1.ListOptions{})
+
+	assert.Len(t, victims, 0)
+}
+
+func TestEligibleJobsSkipsFinished(t *testing.T) {
+	v1job := newJob(
+		NAME,
+		map[string]string{
+			"kube-monkey/identifier": "1",
+			"kube-monkey/mtbf":       "1",
+		},
+	)
+	v1job.Status.Conditions = []batchv1.JobCondition{
+		{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+	}
+
+	client := fake.NewSimpleClientset(&v1job)
+	victims, _ := EligibleJobs(client, NAMESPACE, &metav1.ListOptions{})
+
+	assert.Len(t, victims, 0)
+}
+
+func TestOutcome(t *testing.T) {
+	v1job := newJob(NAME, map[string]string{})
+
+	outcome, finished := Outcome(&v1job)
+	assert.False(t, finished)
+	assert.Equal(t, "Job "+NAME+" has not finished", outcome)
+
+	v1job.Status.Conditions = []batchv1.JobCondition{
+		{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded"},
+	}
+	outcome, finished = Outcome(&v1job)
+	assert.True(t, finished)
+	assert.Equal(t, "Job "+NAME+" failed: BackoffLimitExceeded", outcome)
+
+	v1job.Status.Conditions = []batchv1.JobCondition{
+		{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+	}
+	outcome, finished = Outcome(&v1job)
+	assert.True(t, finished)
+	assert.Equal(t, "Job "+NAME+" completed", outcome)
+}
+
+func TestWaitForCompletion(t *testing.T) {
+	v1job := newJob(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: "1",
+			config.MtbfLabelKey:  "1",
+		},
+	)
+	v1job.Status.Conditions = []batchv1.JobCondition{
+		{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+	}
+
+	job, _ := New(&v1job, nil)
+	client := fake.NewSimpleClientset(&v1job)
+
+	outcome, err := job.WaitForCompletion(client, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "Job "+NAME+" completed", outcome)
+
+	_, err = job.WaitForCompletion(fake.NewSimpleClientset(), time.Second)
+	assert.Error(t, err, "Expected an error if the job no longer exists")
+}
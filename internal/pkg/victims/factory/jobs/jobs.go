@@ -0,0 +1,65 @@
+package jobs
+
+import (
+	"fmt"
+
+	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/victims"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type Job struct {
+	*victims.VictimBase
+}
+
+// New creates a new instance of Job
+// ns is the namespace of the Job if it is enrolled as a whole, and nil otherwise
+func New(job *batchv1.Job, ns *corev1.Namespace) (*Job, error) {
+	ident, err := identifier(job)
+	// The identifier label is optional when pods are found by their selector,
+	// which is always the case for workloads enrolled through their namespace
+	if err != nil && !config.PodDiscoveryBySelector() && ns == nil {
+		return nil, err
+	}
+	mtbf, err := meanTimeBetweenFailures(job, ns)
+	if err != nil {
+		return nil, err
+	}
+	kind := fmt.Sprintf("%T", *job)
+
+	victim := victims.New(kind, job.Name, job.Namespace, ident, mtbf)
+	if err := victim.ConfigurePodDiscovery(job.Spec.Selector, job.UID); err != nil {
+		return nil, err
+	}
+	if err := victim.ConfigureSettings(job, ns); err != nil {
+		return nil, err
+	}
+
+	return &Job{VictimBase: victim}, nil
+}
+
+// Returns the value of the setting defined by config.IdentLabelKey
+// from the job annotations or labels
+// This label should be unique to a job, and is used to
+// identify the pods that belong to this job, as pods
+// inherit labels from the Job's pod template
+func identifier(kubekind *batchv1.Job) (string, error) {
+	identifier, ok := victims.Setting(kubekind, config.IdentLabelKey)
+	if !ok {
+		return "", fmt.Errorf("%T %s does not have %s label or annotation", kubekind, kubekind.Name, config.IdentLabelKey)
+	}
+	return identifier, nil
+}
+
+// Read the mean-time-between-failures value defined by the Job
+// in the setting defined by config.MtbfLabelKey, or the default of its namespace
+func meanTimeBetweenFailures(kubekind *batchv1.Job, ns *corev1.Namespace) (float64, error) {
+	mtbf, ok := victims.SettingOrDefault(kubekind, ns, config.MtbfLabelKey)
+	if !ok {
+		return -1, fmt.Errorf("%T %s does not have %s label or annotation", kubekind, kubekind.Name, config.MtbfLabelKey)
+	}
+
+	return victims.ParseMtbf(mtbf)
+}
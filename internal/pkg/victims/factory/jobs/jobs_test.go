@@ -0,0 +1,93 @@
+package jobs
+
+import (
+	"testing"
+
+	"kube-monkey/internal/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	IDENTIFIER = "kube-monkey-id"
+	NAME       = "job_name"
+	NAMESPACE  = metav1.NamespaceDefault
+)
+
+func newJob(name string, labels map[string]string) batchv1.Job {
+
+	return batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: NAMESPACE,
+			Labels:    labels,
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+
+	v1job := newJob(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: IDENTIFIER,
+			config.MtbfLabelKey:  "1",
+		},
+	)
+	job, err := New(&v1job, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.Job", job.Kind())
+	assert.Equal(t, NAME, job.Name())
+	assert.Equal(t, NAMESPACE, job.Namespace())
+	assert.Equal(t, IDENTIFIER, job.Identifier())
+	assert.Equal(t, float64(1), job.Mtbf())
+}
+
+func TestInvalidIdentifier(t *testing.T) {
+	v1job := newJob(
+		NAME,
+		map[string]string{
+			config.MtbfLabelKey: "1",
+		},
+	)
+	_, err := New(&v1job, nil)
+
+	assert.Errorf(t, err, "Expected an error if "+config.IdentLabelKey+" label doesn't exist")
+}
+
+func TestInvalidMtbf(t *testing.T) {
+	v1job := newJob(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: IDENTIFIER,
+		},
+	)
+	_, err := New(&v1job, nil)
+
+	assert.Errorf(t, err, "Expected an error if "+config.MtbfLabelKey+" label doesn't exist")
+
+	v1job = newJob(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: IDENTIFIER,
+			config.MtbfLabelKey:  "string",
+		},
+	)
+	_, err = New(&v1job, nil)
+
+	assert.Errorf(t, err, "Expected an error if "+config.MtbfLabelKey+" label can't be converted a Int type")
+
+	v1job = newJob(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: IDENTIFIER,
+			config.MtbfLabelKey:  "0",
+		},
+	)
+	_, err = New(&v1job, nil)
+
+	assert.Errorf(t, err, "Expected an error if "+config.MtbfLabelKey+" label is lower than 1")
+}
@@ -0,0 +1,318 @@
+package nodes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+
+	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/victims"
+
+	kube "k8s.io/client-go/kubernetes"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// Interval before the first retry of the evictions that a
+// PodDisruptionBudget refused, doubled after every retry up to
+// maxEvictionRetryInterval
+var (
+	evictionRetryInterval    = 5 * time.Second
+	maxEvictionRetryInterval = time.Minute
+)
+
+// EligibleNodes gets all nodes that opted in (filtered by config.EnabledLabel)
+// to the attack, one of config.NodeAttackDrain and config.NodeAttackTaint
+func EligibleNodes(clientset kube.Interface, filter *metav1.ListOptions, attack string) (eligVictims []victims.Victim, err error) {
+	enabledVictims, err := clientset.CoreV1().Nodes().List(context.TODO(), *filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, vic := range enabledVictims.Items {
+		victim, err := New(&vic)
+		if err != nil {
+			glog.Warningf("Skipping eligible %T %s because of error: %s", vic, vic.Name, err.Error())
+			continue
+		}
+		if !victim.HasAttack(attack) {
+			continue
+		}
+
+		eligVictims = append(eligVictims, victim)
+	}
+
+	return
+}
+
+/* Below methods are used to verify the victim's attributes have not changed at the scheduled time of termination */
+
+// IsEnrolled checks if the node is currently enrolled in kube-monkey, and
+// still opted in to drains, which are the attacks scheduled per node
+func (n *Node) IsEnrolled(clientset kube.Interface) (bool, error) {
+	node, err := clientset.CoreV1().Nodes().Get(context.TODO(), n.Name(), metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+	if node.Labels[config.EnabledLabelKey] != config.EnabledLabelValue {
+		return false, nil
+	}
+	attacks, err := nodeAttacks(node)
+	if err != nil {
+		return false, err
+	}
+	return attacks[config.NodeAttackDrain], nil
+}
+
+// KillType returns an error, since nodes are always drained
+func (n *Node) KillType(clientset kube.Interface) (string, error) {
+	return "", fmt.Errorf("%s %s does not have a %s, nodes are drained", n.Kind(), n.Name(), config.KillTypeLabelKey)
+}
+
+// KillValue returns an error, since nodes are always drained
+func (n *Node) KillValue(clientset kube.Interface) (int, error) {
+	return -1, fmt.Errorf("%s %s does not have a %s, nodes are drained", n.Kind(), n.Name(), config.KillValueLabelKey)
+}
+
+// IsBlacklisted returns false, since nodes are not namespaced. The pods
+// of blacklisted namespaces are left alone when the node is attacked
+func (n *Node) IsBlacklisted(clientset kube.Interface) bool {
+	return false
+}
+
+// IsWhitelisted returns true, since nodes are not namespaced. The pods
+// of non-whitelisted namespaces are left alone when the node is attacked
+func (n *Node) IsWhitelisted(clientset kube.Interface) bool {
+	return true
+}
+
+// Drain cordons the node, evicts its pods through the Eviction API, and
+// uncordons the node after its attack duration. DaemonSet pods, mirror
+// pods and the pods of blacklisted or non-whitelisted namespaces are not
+// evicted, and pods whose eviction fails are reported without stopping
+// the drain. Like kubectl drain, each pod gets its own grace period, and
+// evictions refused by a PodDisruptionBudget are retried with backoff
+// during the attack duration. The pods still blocked at its end are listed
+// in the outcome
+// The node carries the config.CordonedAnnotationKey annotation while it is
+// cordoned, so that ReconcileCordonedNodes uncordons it if kube-monkey
+// restarts during the attack
+func (n *Node) Drain(clientset kube.Interface) (string, []victims.AffectedWorkload, error) {
+	node, err := clientset.CoreV1().Nodes().Get(context.TODO(), n.Name(), metav1.GetOptions{})
+	if err != nil {
+		return "", nil, err
+	}
+	if node.Spec.Unschedulable {
+		return "", nil, fmt.Errorf("%s %s is already cordoned", n.Kind(), n.Name())
+	}
+
+	pods, err := n.evictablePods(clientset)
+	if err != nil {
+		return "", nil, err
+	}
+
+	duration := n.AttackDuration()
+	if config.DryRun() {
+		glog.Infof("[DryRun Mode] Cordoned node %s and evicted %d pods for %s", n.Name(), len(pods), duration)
+		return drainOutcome(n.Name(), len(pods), duration, nil), nil, nil
+	}
+
+	glog.V(6).Infof("Cordoning node %s for %s", n.Name(), duration)
+	if err := cordon(clientset, n.Name(), true); err != nil {
+		return "", nil, errors.Wrapf(err, "Failed to cordon node %s", n.Name())
+	}
+
+	deadline := time.Now().Add(duration)
+	evicted, blocked, errs := evictPods(clientset, pods)
+	for interval := evictionRetryInterval; len(blocked) > 0 && time.Until(deadline) > interval; {
+		time.Sleep(interval)
+
+		retried, stillBlocked, retryErrs := evictPods(clientset, blocked)
+		evicted = append(evicted, retried...)
+		blocked = stillBlocked
+		errs = append(errs, retryErrs...)
+
+		if interval *= 2; interval > maxEvictionRetryInterval {
+			interval = maxEvictionRetryInterval
+		}
+	}
+
+	time.Sleep(time.Until(deadline))
+
+	glog.V(6).Infof("Uncordoning node %s", n.Name())
+	if err := cordon(clientset, n.Name(), false); err != nil {
+		errs = append(errs, errors.Wrapf(err, "Failed to uncordon node %s", n.Name()))
+	}
+
+	var blockedPods []string
+	for _, pod := range blocked {
+		blockedPods = append(blockedPods, pod.Namespace+"/"+pod.Name)
+	}
+	return drainOutcome(n.Name(), len(evicted), duration, blockedPods), affectedWorkloads(evicted), utilerrors.NewAggregate(errs)
+}
+
+// Evicts the pods, and returns the evicted ones, the ones whose eviction
+// a PodDisruptionBudget refused, and the errors of the others. Pods that
+// are already gone are left out
+func evictPods(clientset kube.Interface, pods []corev1.Pod) (evicted, blocked []corev1.Pod, errs []error) {
+	for _, pod := range pods {
+		err := ownerOf(pod).EvictPod(clientset, pod.Name)
+		switch {
+		case err == nil:
+			evicted = append(evicted, pod)
+		case victims.IsBlockedByPDB(err):
+			blocked = append(blocked, pod)
+		case !apierrors.IsNotFound(err):
+			errs = append(errs, err)
+		}
+	}
+	return
+}
+
+// ReconcileCordonedNodes uncordons the nodes left cordoned by drains that
+// were interrupted by a restart of kube-monkey
+// A node that fails to be uncordoned does not stop the others from being
+// uncordoned, and all failures are returned together
+func ReconcileCordonedNodes(clientset kube.Interface) error {
+	nodes, err := clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, node := range nodes.Items {
+		if _, ok := node.Annotations[config.CordonedAnnotationKey]; !ok {
+			continue
+		}
+
+		glog.V(3).Infof("Uncordoning node %s left cordoned by an interrupted drain", node.Name)
+		if err := cordon(clientset, node.Name, false); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, errors.Wrapf(err, "Failed to uncordon node %s", node.Name))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// Lists the running pods on the node that a drain evicts
+func (n *Node) evictablePods(clientset kube.Interface) ([]corev1.Pod, error) {
+	pods, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), nodeFilter(n.Name()))
+	if err != nil {
+		return nil, err
+	}
+
+	protected := map[string]bool{}
+	var evictable []corev1.Pod
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning && pod.Status.Phase != corev1.PodPending {
+			continue
+		}
+		if _, mirror := pod.Annotations[corev1.MirrorPodAnnotationKey]; mirror {
+			continue
+		}
+		if owner := metav1.GetControllerOf(&pod); owner != nil && owner.Kind == "DaemonSet" {
+			continue
+		}
+
+		if isProtected(clientset, protected, pod) {
+			continue
+		}
+		evictable = append(evictable, pod)
+	}
+	return evictable, nil
+}
+
+// Checks if the namespace of the pod is blacklisted or not whitelisted, and
+// keeps the result in checked for the other pods of the namespace
+func isProtected(clientset kube.Interface, checked map[string]bool, pod corev1.Pod) bool {
+	protected, ok := checked[pod.Namespace]
+	if !ok {
+		owner := ownerOf(pod)
+		protected = owner.IsBlacklisted(clientset) || !owner.IsWhitelisted(clientset)
+		checked[pod.Namespace] = protected
+	}
+	return protected
+}
+
+// Filters for the pods on the node
+func nodeFilter(name string) metav1.ListOptions {
+	return metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", name).String(),
+	}
+}
+
+// Returns a victim standing for the controller of the pod, or the pod itself
+// if it is not controlled, to evict the pod and report it as affected
+// The victim evicts pods with their own grace period
+func ownerOf(pod corev1.Pod) *victims.VictimBase {
+	var victim *victims.VictimBase
+	if owner := metav1.GetControllerOf(&pod); owner != nil {
+		victim = victims.New(owner.Kind, owner.Name, pod.Namespace, "", 0)
+	} else {
+		victim = victims.New("Pod", pod.Name, pod.Namespace, "", 0)
+	}
+	victim.UsePodGracePeriod()
+	return victim
+}
+
+// Groups the evicted pods by their controller
+func affectedWorkloads(pods []corev1.Pod) []victims.AffectedWorkload {
+	var affected []victims.AffectedWorkload
+	index := map[string]int{}
+	for _, pod := range pods {
+		owner := ownerOf(pod)
+		key := owner.Kind() + "/" + owner.Namespace() + "/" + owner.Name()
+		i, ok := index[key]
+		if !ok {
+			i = len(affected)
+			index[key] = i
+			affected = append(affected, victims.AffectedWorkload{Kind: owner.Kind(), Namespace: owner.Namespace(), Name: owner.Name()})
+		}
+		affected[i].Pods = append(affected[i].Pods, pod.Name)
+	}
+
+	for i := range affected {
+		sort.Strings(affected[i].Pods)
+	}
+	return affected
+}
+
+func drainOutcome(node string, evicted int, duration time.Duration, blocked []string) string {
+	outcome := fmt.Sprintf("Cordoned node %s and evicted %d pods, then uncordoned it after %s", node, evicted, duration)
+	if len(blocked) > 0 {
+		outcome += fmt.Sprintf("; %d pods stayed because a PodDisruptionBudget refused their eviction: %s", len(blocked), strings.Join(blocked, ", "))
+	}
+	return outcome
+}
+
+// Cordons or uncordons the node, and sets or removes the
+// config.CordonedAnnotationKey annotation accordingly
+func cordon(clientset kube.Interface, name string, unschedulable bool) error {
+	var cordoned interface{}
+	if unschedulable {
+		cordoned = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				config.CordonedAnnotationKey: cordoned,
+			},
+		},
+		"spec": map[string]interface{}{
+			"unschedulable": unschedulable,
+		},
+	})
+	_, err := clientset.CoreV1().Nodes().Patch(context.TODO(), name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
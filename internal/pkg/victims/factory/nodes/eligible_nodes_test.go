@@ -0,0 +1,263 @@
+package nodes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/config/param"
+	"kube-monkey/internal/pkg/victims"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newEnrolledNode() corev1.Node {
+	return newNode(
+		NAME,
+		map[string]string{
+			config.EnabledLabelKey:        config.EnabledLabelValue,
+			config.MtbfLabelKey:           "1",
+			config.AttackDurationLabelKey: "1ms",
+		},
+	)
+}
+
+func newPod(name, namespace string, owner *metav1.OwnerReference) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       corev1.PodSpec{NodeName: NAME},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if owner != nil {
+		pod.OwnerReferences = []metav1.OwnerReference{*owner}
+	}
+	return pod
+}
+
+func controller(kind, name string) *metav1.OwnerReference {
+	controller := true
+	return &metav1.OwnerReference{Kind: kind, Name: name, Controller: &controller}
+}
+
+// Records the evicted pods, or refuses the eviction of blocked ones as a
+// PodDisruptionBudget would
+func evictionReactor(evicted *[]string, blocked string) k8stesting.ReactionFunc {
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		eviction := action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction)
+		if eviction.Name == blocked {
+			return true, nil, apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+		}
+		*evicted = append(*evicted, eviction.Name)
+		return true, nil, nil
+	}
+}
+
+func TestEligibleNodes(t *testing.T) {
+	enrolled := newEnrolledNode()
+	other := newNode("node-2", map[string]string{})
+	client := fake.NewSimpleClientset(&enrolled, &other)
+
+	filter := &metav1.ListOptions{LabelSelector: config.EnabledLabelKey + "=" + config.EnabledLabelValue}
+	eligible, err := EligibleNodes(client, filter, config.NodeAttackDrain)
+	assert.NoError(t, err)
+	if assert.Len(t, eligible, 1) {
+		assert.Equal(t, NAME, eligible[0].Name())
+		assert.Implements(t, (*victims.NodeVictim)(nil), eligible[0])
+	}
+
+	eligible, err = EligibleNodes(client, filter, config.NodeAttackTaint)
+	assert.NoError(t, err)
+	assert.Empty(t, eligible, "Expected nodes to only be drained by default")
+}
+
+func TestEligibleNodesByAttack(t *testing.T) {
+	tainted := newNode("node-taint", map[string]string{
+		config.EnabledLabelKey:    config.EnabledLabelValue,
+		config.NodeAttackLabelKey: config.NodeAttackTaint,
+	})
+	both := newEnrolledNode()
+	both.Labels[config.NodeAttackLabelKey] = "drain,taint"
+	client := fake.NewSimpleClientset(&tainted, &both)
+
+	filter := &metav1.ListOptions{LabelSelector: config.EnabledLabelKey + "=" + config.EnabledLabelValue}
+	drained, err := EligibleNodes(client, filter, config.NodeAttackDrain)
+	assert.NoError(t, err)
+	if assert.Len(t, drained, 1) {
+		assert.Equal(t, NAME, drained[0].Name())
+	}
+
+	taintable, err := EligibleNodes(client, filter, config.NodeAttackTaint)
+	assert.NoError(t, err)
+	assert.Len(t, taintable, 2)
+}
+
+func TestIsEnrolled(t *testing.T) {
+	v1node := newEnrolledNode()
+	node, _ := New(&v1node)
+	client := fake.NewSimpleClientset(&v1node)
+
+	enrolled, err := node.IsEnrolled(client)
+	assert.NoError(t, err)
+	assert.True(t, enrolled)
+
+	v1node.Labels[config.NodeAttackLabelKey] = config.NodeAttackTaint
+	client = fake.NewSimpleClientset(&v1node)
+	enrolled, _ = node.IsEnrolled(client)
+	assert.False(t, enrolled, "Expected node to not be enrolled in drains")
+
+	v1node.Labels[config.EnabledLabelKey] = "x"
+	delete(v1node.Labels, config.NodeAttackLabelKey)
+	client = fake.NewSimpleClientset(&v1node)
+	enrolled, _ = node.IsEnrolled(client)
+	assert.False(t, enrolled, "Expected node to not be enrolled")
+}
+
+func TestDrain(t *testing.T) {
+	defer viper.Set(param.DryRun, viper.GetBool(param.DryRun))
+	defer viper.Set(param.BlacklistedNamespaces, viper.GetStringSlice(param.BlacklistedNamespaces))
+	defer viper.Set(param.WhitelistedNamespaces, viper.GetStringSlice(param.WhitelistedNamespaces))
+	viper.Set(param.DryRun, false)
+	viper.Set(param.BlacklistedNamespaces, []string{metav1.NamespaceSystem})
+	viper.Set(param.WhitelistedNamespaces, []string{metav1.NamespaceDefault, metav1.NamespaceSystem})
+
+	v1node := newEnrolledNode()
+	node, _ := New(&v1node)
+
+	mirror := newPod("mirror", metav1.NamespaceDefault, nil)
+	mirror.Annotations = map[string]string{corev1.MirrorPodAnnotationKey: "mirror"}
+	client := fake.NewSimpleClientset(
+		&v1node,
+		newPod("app-1", metav1.NamespaceDefault, controller("ReplicaSet", "app")),
+		newPod("app-2", metav1.NamespaceDefault, controller("ReplicaSet", "app")),
+		newPod("bare", metav1.NamespaceDefault, nil),
+		newPod("blocked", metav1.NamespaceDefault, nil),
+		newPod("agent", metav1.NamespaceDefault, controller("DaemonSet", "agent")),
+		newPod("dns", metav1.NamespaceSystem, nil),
+		newPod("other", "other", nil),
+		mirror,
+	)
+	var evicted []string
+	client.PrependReactor("create", "pods", evictionReactor(&evicted, "blocked"))
+	client.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() == "eviction" {
+			eviction := action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction)
+			assert.Nil(t, eviction.DeleteOptions.GracePeriodSeconds, "Expected pod %s to be evicted with its own grace period", eviction.Name)
+		}
+		return false, nil, nil
+	})
+	var cordoned []bool
+	client.PrependReactor("patch", "nodes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		node, _ := client.Tracker().Get(corev1.SchemeGroupVersion.WithResource("nodes"), "", NAME)
+		cordoned = append(cordoned, !node.(*corev1.Node).Spec.Unschedulable)
+		return false, nil, nil
+	})
+
+	outcome, affected, err := node.Drain(client)
+	assert.NoError(t, err, "Expected the blocked eviction to be reported in the outcome")
+	assert.Equal(t, "Cordoned node "+NAME+" and evicted 3 pods, then uncordoned it after 1ms; 1 pods stayed because a PodDisruptionBudget refused their eviction: default/blocked", outcome)
+	assert.Equal(t, []string{"app-1", "app-2", "bare"}, evicted)
+	assert.Equal(t, []victims.AffectedWorkload{
+		{Kind: "ReplicaSet", Namespace: metav1.NamespaceDefault, Name: "app", Pods: []string{"app-1", "app-2"}},
+		{Kind: "Pod", Namespace: metav1.NamespaceDefault, Name: "bare", Pods: []string{"bare"}},
+	}, affected)
+	assert.Equal(t, []bool{true, false}, cordoned, "Expected the node to be cordoned and then uncordoned")
+
+	uncordoned, _ := client.CoreV1().Nodes().Get(context.TODO(), NAME, metav1.GetOptions{})
+	assert.False(t, uncordoned.Spec.Unschedulable)
+	assert.NotContains(t, uncordoned.Annotations, config.CordonedAnnotationKey)
+}
+
+func TestDrainRetriesBlockedEvictions(t *testing.T) {
+	defer viper.Set(param.DryRun, viper.GetBool(param.DryRun))
+	defer viper.Set(param.WhitelistedNamespaces, viper.GetStringSlice(param.WhitelistedNamespaces))
+	viper.Set(param.DryRun, false)
+	viper.Set(param.WhitelistedNamespaces, []string{metav1.NamespaceAll})
+	defer func(interval time.Duration) { evictionRetryInterval = interval }(evictionRetryInterval)
+	evictionRetryInterval = time.Millisecond
+
+	v1node := newEnrolledNode()
+	v1node.Labels[config.AttackDurationLabelKey] = "1s"
+	node, _ := New(&v1node)
+	client := fake.NewSimpleClientset(&v1node, newPod("app-1", metav1.NamespaceDefault, controller("ReplicaSet", "app")))
+
+	// The PodDisruptionBudget refuses the first two evictions
+	var evicted []string
+	refusals := 2
+	client.PrependReactor("create", "pods", evictionReactor(&evicted, ""))
+	client.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" || refusals == 0 {
+			return false, nil, nil
+		}
+		refusals--
+		return true, nil, apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+	})
+
+	outcome, affected, err := node.Drain(client)
+	assert.NoError(t, err)
+	assert.Equal(t, "Cordoned node "+NAME+" and evicted 1 pods, then uncordoned it after 1s", outcome)
+	assert.Equal(t, []string{"app-1"}, evicted, "Expected the refused eviction to be retried")
+	assert.Len(t, affected, 1)
+}
+
+func TestDrainAlreadyCordoned(t *testing.T) {
+	v1node := newEnrolledNode()
+	v1node.Spec.Unschedulable = true
+	node, _ := New(&v1node)
+	client := fake.NewSimpleClientset(&v1node)
+
+	_, _, err := node.Drain(client)
+	assert.EqualError(t, err, node.Kind()+" "+NAME+" is already cordoned")
+}
+
+func TestReconcileCordonedNodes(t *testing.T) {
+	cordoned := newNode(NAME, map[string]string{})
+	cordoned.Spec.Unschedulable = true
+	cordoned.Annotations = map[string]string{config.CordonedAnnotationKey: "2024-01-01T00:00:00Z"}
+	maintenance := newNode("node-2", map[string]string{})
+	maintenance.Spec.Unschedulable = true
+	client := fake.NewSimpleClientset(&cordoned, &maintenance)
+
+	assert.NoError(t, ReconcileCordonedNodes(client))
+
+	node, _ := client.CoreV1().Nodes().Get(context.TODO(), NAME, metav1.GetOptions{})
+	assert.False(t, node.Spec.Unschedulable)
+	assert.NotContains(t, node.Annotations, config.CordonedAnnotationKey)
+
+	node, _ = client.CoreV1().Nodes().Get(context.TODO(), "node-2", metav1.GetOptions{})
+	assert.True(t, node.Spec.Unschedulable, "Expected nodes cordoned by others to stay cordoned")
+}
+
+func TestReconcileCordonedNodesContinuesAfterFailure(t *testing.T) {
+	failing := newNode("node-0", map[string]string{})
+	failing.Spec.Unschedulable = true
+	failing.Annotations = map[string]string{config.CordonedAnnotationKey: "2024-01-01T00:00:00Z"}
+	cordoned := newNode(NAME, map[string]string{})
+	cordoned.Spec.Unschedulable = true
+	cordoned.Annotations = map[string]string{config.CordonedAnnotationKey: "2024-01-01T00:00:00Z"}
+	client := fake.NewSimpleClientset(&failing, &cordoned)
+	client.PrependReactor("patch", "nodes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.(k8stesting.PatchAction).GetName() == "node-0" {
+			return true, nil, apierrors.NewForbidden(corev1.Resource("nodes"), "node-0", nil)
+		}
+		return false, nil, nil
+	})
+
+	err := ReconcileCordonedNodes(client)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Failed to uncordon node node-0")
+
+	node, _ := client.CoreV1().Nodes().Get(context.TODO(), NAME, metav1.GetOptions{})
+	assert.False(t, node.Spec.Unschedulable, "Expected the other nodes to be uncordoned")
+}
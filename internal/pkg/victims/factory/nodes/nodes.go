@@ -0,0 +1,81 @@
+package nodes
+
+import (
+	"fmt"
+	"strings"
+
+	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/victims"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type Node struct {
+	*victims.VictimBase
+	attacks map[string]bool
+}
+
+// New creates a new instance of Node
+// Nodes are not namespaced, so their settings have no namespace defaults
+// Only nodes opted in to drains need a mtbf, taints are scheduled by the
+// global node_taint_mtbf
+func New(node *corev1.Node) (*Node, error) {
+	attacks, err := nodeAttacks(node)
+	if err != nil {
+		return nil, err
+	}
+
+	mtbf := float64(0)
+	if attacks[config.NodeAttackDrain] {
+		if mtbf, err = meanTimeBetweenFailures(node); err != nil {
+			return nil, err
+		}
+	}
+	kind := fmt.Sprintf("%T", *node)
+
+	victim := victims.New(kind, node.Name, metav1.NamespaceNone, node.Name, mtbf)
+	if err := victim.ConfigureSettings(node, nil); err != nil {
+		return nil, err
+	}
+
+	return &Node{VictimBase: victim, attacks: attacks}, nil
+}
+
+// HasAttack returns whether the node opted in to the attack, one of
+// config.NodeAttackDrain and config.NodeAttackTaint
+func (n *Node) HasAttack(attack string) bool {
+	return n.attacks[attack]
+}
+
+// Read the attacks the Node opted in to from the label or annotation
+// defined by config.NodeAttackLabelKey, which defaults to drains
+func nodeAttacks(kubekind *corev1.Node) (map[string]bool, error) {
+	value, ok := victims.Setting(kubekind, config.NodeAttackLabelKey)
+	if !ok {
+		return map[string]bool{config.NodeAttackDrain: true}, nil
+	}
+
+	attacks := map[string]bool{}
+	for _, attack := range strings.Split(value, ",") {
+		attack = strings.TrimSpace(attack)
+		switch attack {
+		case config.NodeAttackDrain, config.NodeAttackTaint:
+			attacks[attack] = true
+		default:
+			return nil, fmt.Errorf("%T %s has invalid %s %q, expected a comma-separated list of %s and %s", kubekind, kubekind.Name, config.NodeAttackLabelKey, value, config.NodeAttackDrain, config.NodeAttackTaint)
+		}
+	}
+	return attacks, nil
+}
+
+// Read the mean-time-between-failures value defined by the Node
+// in the label or annotation defined by config.MtbfLabelKey
+func meanTimeBetweenFailures(kubekind *corev1.Node) (float64, error) {
+	mtbf, ok := victims.Setting(kubekind, config.MtbfLabelKey)
+	if !ok {
+		return -1, fmt.Errorf("%T %s does not have %s label or annotation", kubekind, kubekind.Name, config.MtbfLabelKey)
+	}
+
+	return victims.ParseMtbf(mtbf)
+}
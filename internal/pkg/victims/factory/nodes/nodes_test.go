@@ -0,0 +1,71 @@
+package nodes
+
+import (
+	"testing"
+
+	"kube-monkey/internal/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const NAME = "node-1"
+
+func newNode(name string, labels map[string]string) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	v1node := newNode(
+		NAME,
+		map[string]string{
+			config.EnabledLabelKey:        config.EnabledLabelValue,
+			config.MtbfLabelKey:           "2",
+			config.AttackDurationLabelKey: "10m",
+		},
+	)
+	node, err := New(&v1node)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.Node", node.Kind())
+	assert.Equal(t, NAME, node.Name())
+	assert.Equal(t, "", node.Namespace())
+	assert.Equal(t, float64(2), node.Mtbf())
+	assert.Equal(t, "10m0s", node.AttackDuration().String())
+	assert.True(t, node.HasAttack(config.NodeAttackDrain), "Expected nodes to be drained by default")
+	assert.False(t, node.HasAttack(config.NodeAttackTaint))
+}
+
+func TestInvalidMtbf(t *testing.T) {
+	v1node := newNode(NAME, map[string]string{config.EnabledLabelKey: config.EnabledLabelValue})
+	_, err := New(&v1node)
+	assert.EqualError(t, err, "*v1.Node "+NAME+" does not have "+config.MtbfLabelKey+" label or annotation")
+}
+
+func TestNewTaintOnly(t *testing.T) {
+	v1node := newNode(NAME, map[string]string{
+		config.EnabledLabelKey:    config.EnabledLabelValue,
+		config.NodeAttackLabelKey: config.NodeAttackTaint,
+	})
+	node, err := New(&v1node)
+
+	assert.NoError(t, err, "Expected a mtbf to only be required for drains")
+	assert.True(t, node.HasAttack(config.NodeAttackTaint))
+	assert.False(t, node.HasAttack(config.NodeAttackDrain))
+}
+
+func TestInvalidNodeAttack(t *testing.T) {
+	v1node := newNode(NAME, map[string]string{
+		config.EnabledLabelKey:    config.EnabledLabelValue,
+		config.MtbfLabelKey:       "2",
+		config.NodeAttackLabelKey: "drain,reboot",
+	})
+	_, err := New(&v1node)
+	assert.EqualError(t, err, "*v1.Node "+NAME+" has invalid "+config.NodeAttackLabelKey+" \"drain,reboot\", expected a comma-separated list of drain and taint")
+}
@@ -0,0 +1,212 @@
+package nodes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+
+	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/victims"
+
+	kube "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The taint applied to nodes, see config.TaintKey
+var chaosTaint = corev1.Taint{Key: config.TaintKey, Effect: corev1.TaintEffectNoExecute}
+
+// Taint applies a NoExecute taint to the node for its attack duration, and
+// then removes it. The pods on the node that do not tolerate the taint are
+// evicted by Kubernetes, and returned as the affected workloads. The pods
+// that stayed because of their tolerations are listed in the outcome
+// The taint lets ReconcileTaintedNodes remove it if kube-monkey restarts
+// during the attack
+// Unlike a drain, a taint cannot spare single pods, so nodes hosting pods of
+// blacklisted or non-whitelisted namespaces that do not tolerate the taint
+// are refused, as are nodes already tainted by another attack
+func (n *Node) Taint(clientset kube.Interface) (string, []victims.AffectedWorkload, error) {
+	before, err := podsOnNode(clientset, n.Name())
+	if err != nil {
+		return "", nil, err
+	}
+	if protected := protectedPods(clientset, before); len(protected) > 0 {
+		return "", nil, fmt.Errorf("%s %s hosts pods of blacklisted or non-whitelisted namespaces that do not tolerate %s: %s", n.Kind(), n.Name(), chaosTaint.ToString(), strings.Join(protected, ", "))
+	}
+
+	duration := n.AttackDuration()
+	if config.DryRun() {
+		glog.Infof("[DryRun Mode] Tainted node %s with %s for %s", n.Name(), chaosTaint.ToString(), duration)
+		return fmt.Sprintf("Tainted node %s with %s for %s", n.Name(), chaosTaint.ToString(), duration), nil, nil
+	}
+
+	glog.V(6).Infof("Tainting node %s with %s for %s", n.Name(), chaosTaint.ToString(), duration)
+	if err := updateTaints(clientset, n.Name(), true); err != nil {
+		return "", nil, errors.Wrapf(err, "Failed to taint node %s", n.Name())
+	}
+
+	time.Sleep(duration)
+
+	// Check which pods left before the taint is removed
+	after, listErr := podsOnNode(clientset, n.Name())
+
+	glog.V(6).Infof("Removing taint %s from node %s", chaosTaint.ToString(), n.Name())
+	if err := updateTaints(clientset, n.Name(), false); err != nil {
+		return "", nil, errors.Wrapf(err, "Failed to remove taint %s from node %s", chaosTaint.ToString(), n.Name())
+	}
+	if listErr != nil {
+		return "", nil, errors.Wrapf(listErr, "Failed to check the pods evicted from node %s", n.Name())
+	}
+
+	remaining := map[string]corev1.Pod{}
+	for _, pod := range after {
+		if pod.DeletionTimestamp == nil {
+			remaining[pod.Namespace+"/"+pod.Name] = pod
+		}
+	}
+
+	var evicted []corev1.Pod
+	var tolerated, pending []string
+	for _, pod := range before {
+		key := pod.Namespace + "/" + pod.Name
+		if _, ok := remaining[key]; !ok {
+			evicted = append(evicted, pod)
+		} else if tolerates(pod, chaosTaint) {
+			tolerated = append(tolerated, key)
+		} else {
+			pending = append(pending, key)
+		}
+	}
+
+	return taintOutcome(n.Name(), duration, len(evicted), tolerated, pending), affectedWorkloads(evicted), nil
+}
+
+// ReconcileTaintedNodes removes the taints left on nodes by taints that were
+// interrupted by a restart of kube-monkey
+func ReconcileTaintedNodes(clientset kube.Interface) error {
+	nodes, err := clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodes.Items {
+		if !hasTaint(&node) {
+			continue
+		}
+
+		glog.V(3).Infof("Removing taint %s left on node %s by an interrupted attack", chaosTaint.ToString(), node.Name)
+		if err := updateTaints(clientset, node.Name, false); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Lists the running pods on the node, leaving out mirror pods, which are
+// managed by the kubelet and never evicted
+func podsOnNode(clientset kube.Interface, name string) ([]corev1.Pod, error) {
+	pods, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), nodeFilter(name))
+	if err != nil {
+		return nil, err
+	}
+
+	var running []corev1.Pod
+	for _, pod := range pods.Items {
+		if _, mirror := pod.Annotations[corev1.MirrorPodAnnotationKey]; mirror {
+			continue
+		}
+		if pod.Status.Phase == corev1.PodRunning {
+			running = append(running, pod)
+		}
+	}
+	return running, nil
+}
+
+// Lists the pods of blacklisted or non-whitelisted namespaces that the
+// taint would evict
+func protectedPods(clientset kube.Interface, pods []corev1.Pod) []string {
+	checked := map[string]bool{}
+	var protected []string
+	for _, pod := range pods {
+		if !tolerates(pod, chaosTaint) && isProtected(clientset, checked, pod) {
+			protected = append(protected, pod.Namespace+"/"+pod.Name)
+		}
+	}
+	return protected
+}
+
+func tolerates(pod corev1.Pod, taint corev1.Taint) bool {
+	for _, toleration := range pod.Spec.Tolerations {
+		if toleration.ToleratesTaint(&taint) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasTaint(node *corev1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.MatchTaint(&chaosTaint) {
+			return true
+		}
+	}
+	return false
+}
+
+// Adds the taint to the node, or removes it
+// Adding fails if the node already carries the taint, since removing it at
+// the end would cut the attack that added it short
+func updateTaints(clientset kube.Interface, name string, tainted bool) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		node, err := clientset.CoreV1().Nodes().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		switch {
+		case tainted && hasTaint(node):
+			return fmt.Errorf("node %s is already tainted with %s", name, chaosTaint.ToString())
+		case !tainted && !hasTaint(node):
+			return nil
+		}
+
+		return setTaint(clientset, node, tainted)
+	})
+}
+
+// Updates the taints of the node with the taint added or removed
+func setTaint(clientset kube.Interface, node *corev1.Node, tainted bool) error {
+	taints := []corev1.Taint{}
+	for _, taint := range node.Spec.Taints {
+		if !taint.MatchTaint(&chaosTaint) {
+			taints = append(taints, taint)
+		}
+	}
+	if tainted {
+		taint := chaosTaint
+		now := metav1.Now()
+		taint.TimeAdded = &now
+		taints = append(taints, taint)
+	}
+
+	node.Spec.Taints = taints
+	_, err := clientset.CoreV1().Nodes().Update(context.TODO(), node, metav1.UpdateOptions{})
+	return err
+}
+
+func taintOutcome(node string, duration time.Duration, evicted int, tolerated, pending []string) string {
+	outcome := fmt.Sprintf("Tainted node %s with %s for %s, evicting %d pods", node, chaosTaint.ToString(), duration, evicted)
+	if len(tolerated) > 0 {
+		outcome += fmt.Sprintf("; %d pods stayed because of their tolerations: %s", len(tolerated), strings.Join(tolerated, ", "))
+	}
+	if len(pending) > 0 {
+		outcome += fmt.Sprintf("; %d pods were not evicted yet: %s", len(pending), strings.Join(pending, ", "))
+	}
+	return outcome
+}
@@ -0,0 +1,120 @@
+package nodes
+
+import (
+	"context"
+	"testing"
+
+	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/config/param"
+	"kube-monkey/internal/pkg/victims"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestTaint(t *testing.T) {
+	defer viper.Set(param.DryRun, viper.GetBool(param.DryRun))
+	defer viper.Set(param.WhitelistedNamespaces, viper.GetStringSlice(param.WhitelistedNamespaces))
+	viper.Set(param.DryRun, false)
+	viper.Set(param.WhitelistedNamespaces, []string{metav1.NamespaceAll})
+
+	v1node := newEnrolledNode()
+	v1node.Spec.Taints = []corev1.Taint{{Key: "dedicated", Value: "db", Effect: corev1.TaintEffectNoSchedule}}
+	node, _ := New(&v1node)
+
+	tolerant := newPod("agent", metav1.NamespaceDefault, nil)
+	tolerant.Spec.Tolerations = []corev1.Toleration{{Operator: corev1.TolerationOpExists}}
+	client := fake.NewSimpleClientset(
+		&v1node,
+		newPod("app-1", metav1.NamespaceDefault, controller("ReplicaSet", "app")),
+		newPod("app-2", metav1.NamespaceDefault, controller("ReplicaSet", "app")),
+		tolerant,
+	)
+
+	// Evicts the pods that do not tolerate the taint, as the taint manager would
+	var taints [][]corev1.Taint
+	client.PrependReactor("update", "nodes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		updated := action.(k8stesting.UpdateAction).GetObject().(*corev1.Node)
+		taints = append(taints, updated.Spec.Taints)
+		if hasTaint(updated) {
+			for _, pod := range []string{"app-1", "app-2"} {
+				_ = client.Tracker().Delete(corev1.SchemeGroupVersion.WithResource("pods"), metav1.NamespaceDefault, pod)
+			}
+		}
+		return false, nil, nil
+	})
+
+	outcome, affected, err := node.Taint(client)
+	assert.NoError(t, err)
+	assert.Equal(t, "Tainted node "+NAME+" with "+config.TaintKey+":NoExecute for 1ms, evicting 2 pods; 1 pods stayed because of their tolerations: default/agent", outcome)
+	assert.Equal(t, []victims.AffectedWorkload{
+		{Kind: "ReplicaSet", Namespace: metav1.NamespaceDefault, Name: "app", Pods: []string{"app-1", "app-2"}},
+	}, affected)
+	if assert.Len(t, taints, 2) {
+		assert.Len(t, taints[0], 2, "Expected the taint to be added to the existing ones")
+		assert.Equal(t, v1node.Spec.Taints, taints[1], "Expected only the taint of kube-monkey to be removed")
+	}
+}
+
+func TestTaintAlreadyTainted(t *testing.T) {
+	defer viper.Set(param.DryRun, viper.GetBool(param.DryRun))
+	viper.Set(param.DryRun, false)
+
+	v1node := newEnrolledNode()
+	v1node.Spec.Taints = []corev1.Taint{chaosTaint}
+	node, _ := New(&v1node)
+	client := fake.NewSimpleClientset(&v1node)
+
+	_, _, err := node.Taint(client)
+	assert.EqualError(t, err, "Failed to taint node "+NAME+": node "+NAME+" is already tainted with "+config.TaintKey+":NoExecute")
+
+	updated, _ := client.CoreV1().Nodes().Get(context.TODO(), NAME, metav1.GetOptions{})
+	assert.Len(t, updated.Spec.Taints, 1, "Expected the taint of the other attack to stay")
+}
+
+func TestTaintRefusesBlacklistedPods(t *testing.T) {
+	defer viper.Set(param.DryRun, viper.GetBool(param.DryRun))
+	defer viper.Set(param.BlacklistedNamespaces, viper.GetStringSlice(param.BlacklistedNamespaces))
+	defer viper.Set(param.WhitelistedNamespaces, viper.GetStringSlice(param.WhitelistedNamespaces))
+	viper.Set(param.DryRun, false)
+	viper.Set(param.BlacklistedNamespaces, []string{metav1.NamespaceSystem})
+	viper.Set(param.WhitelistedNamespaces, []string{metav1.NamespaceAll})
+
+	v1node := newEnrolledNode()
+	node, _ := New(&v1node)
+	tolerant := newPod("proxy", metav1.NamespaceSystem, controller("DaemonSet", "proxy"))
+	tolerant.Spec.Tolerations = []corev1.Toleration{{Operator: corev1.TolerationOpExists}}
+	client := fake.NewSimpleClientset(
+		&v1node,
+		newPod("app-1", metav1.NamespaceDefault, controller("ReplicaSet", "app")),
+		newPod("dns", metav1.NamespaceSystem, controller("ReplicaSet", "dns")),
+		tolerant,
+	)
+
+	_, _, err := node.Taint(client)
+	assert.EqualError(t, err, "v1.Node "+NAME+" hosts pods of blacklisted or non-whitelisted namespaces that do not tolerate "+config.TaintKey+":NoExecute: kube-system/dns")
+
+	updated, _ := client.CoreV1().Nodes().Get(context.TODO(), NAME, metav1.GetOptions{})
+	assert.Empty(t, updated.Spec.Taints, "Expected the node to stay untainted")
+}
+
+func TestReconcileTaintedNodes(t *testing.T) {
+	tainted := newNode(NAME, map[string]string{})
+	tainted.Spec.Taints = []corev1.Taint{chaosTaint}
+	other := newNode("node-2", map[string]string{})
+	other.Spec.Taints = []corev1.Taint{{Key: "dedicated", Effect: corev1.TaintEffectNoExecute}}
+	client := fake.NewSimpleClientset(&tainted, &other)
+
+	assert.NoError(t, ReconcileTaintedNodes(client))
+
+	node, _ := client.CoreV1().Nodes().Get(context.TODO(), NAME, metav1.GetOptions{})
+	assert.Empty(t, node.Spec.Taints)
+
+	node, _ = client.CoreV1().Nodes().Get(context.TODO(), "node-2", metav1.GetOptions{})
+	assert.Len(t, node.Spec.Taints, 1, "Expected the taints of others to stay")
+}
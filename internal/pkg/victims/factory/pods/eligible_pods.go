@@ -0,0 +1,94 @@
+package pods
+
+//All these functions require api access specific to the version of the app
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/golang/glog"
+
+	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/victims"
+
+	kube "k8s.io/client-go/kubernetes"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EligiblePods gets all eligible bare pods that opted in (filtered by config.EnabledLabel)
+// Pods managed by a controller are skipped, as they inherit the enrollment
+// labels from their workload and are terminated through it
+func EligiblePods(clientset kube.Interface, namespace string, filter *metav1.ListOptions) (eligVictims []victims.Victim, err error) {
+	enabledVictims, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), *filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, vic := range enabledVictims.Items {
+		if metav1.GetControllerOf(&vic) != nil {
+			continue
+		}
+
+		victim, err := New(&vic)
+		if err != nil {
+			glog.Warningf("Skipping eligible %T %s because of error: %s", vic, vic.Name, err.Error())
+			continue
+		}
+
+		if victim.IsBlacklisted(clientset) {
+			continue
+		}
+
+		eligVictims = append(eligVictims, victim)
+	}
+
+	return
+}
+
+/* Below methods are used to verify the victim's attributes have not changed at the scheduled time of termination */
+
+// IsEnrolled checks if the pod is currently enrolled in kube-monkey
+func (p *Pod) IsEnrolled(clientset kube.Interface) (bool, error) {
+	pod, err := clientset.CoreV1().Pods(p.Namespace()).Get(context.TODO(), p.Name(), metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+	return pod.Labels[config.EnabledLabelKey] == config.EnabledLabelValue, nil
+}
+
+// KillType returns current killtype config label for update
+func (p *Pod) KillType(clientset kube.Interface) (string, error) {
+	pod, err := clientset.CoreV1().Pods(p.Namespace()).Get(context.TODO(), p.Name(), metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	killType, ok := pod.Labels[config.KillTypeLabelKey]
+	if !ok {
+		return "", fmt.Errorf("%s %s does not have %s label", p.Kind(), p.Name(), config.KillTypeLabelKey)
+	}
+
+	return killType, nil
+}
+
+// KillValue returns current killvalue config label for update
+func (p *Pod) KillValue(clientset kube.Interface) (int, error) {
+	pod, err := clientset.CoreV1().Pods(p.Namespace()).Get(context.TODO(), p.Name(), metav1.GetOptions{})
+	if err != nil {
+		return -1, err
+	}
+
+	killMode, ok := pod.Labels[config.KillValueLabelKey]
+	if !ok {
+		return -1, fmt.Errorf("%s %s does not have %s label", p.Kind(), p.Name(), config.KillValueLabelKey)
+	}
+
+	killModeInt, err := strconv.Atoi(killMode)
+	if err != nil || !(killModeInt > 0) {
+		return -1, fmt.Errorf("Invalid value for label %s: %d", config.KillValueLabelKey, killModeInt)
+	}
+
+	return killModeInt, nil
+}
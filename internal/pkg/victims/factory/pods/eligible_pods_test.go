@@ -0,0 +1,218 @@
+package pods
+
+import (
+	"testing"
+
+	"kube-monkey/internal/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEligiblePods(t *testing.T) {
+	v1pod := newPod(
+		NAME,
+		map[string]string{
+			"kube-monkey/identifier": "1",
+			"kube-monkey/mtbf":       "1",
+		},
+	)
+
+	client := fake.NewSimpleClientset(&v1pod)
+	victims, _ := EligiblePods(client, NAMESPACE, &metav1.ListOptions{})
+
+	assert.Len(t, victims, 1)
+}
+
+func TestIsEnrolled(t *testing.T) {
+	v1pod := newPod(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey:   "1",
+			config.MtbfLabelKey:    "1",
+			config.EnabledLabelKey: config.EnabledLabelValue,
+		},
+	)
+
+	pod, _ := New(&v1pod)
+
+	client := fake.NewSimpleClientset(&v1pod)
+
+	b, _ := pod.IsEnrolled(client)
+
+	assert.Equal(t, b, true, "Expected pod to be enrolled")
+}
+
+func TestIsNotEnrolled(t *testing.T) {
+	v1pod := newPod(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey:   "1",
+			config.MtbfLabelKey:    "1",
+			config.EnabledLabelKey: "x",
+		},
+	)
+
+	pod, _ := New(&v1pod)
+
+	client := fake.NewSimpleClientset(&v1pod)
+
+	b, _ := pod.IsEnrolled(client)
+
+	assert.Equal(t, b, false, "Expected pod to not be enrolled")
+}
+
+func TestKillType(t *testing.T) {
+
+	ident := "1"
+	mtbf := "1"
+	killMode := "kill-mode"
+
+	v1pod := newPod(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: ident,
+			config.MtbfLabelKey:  mtbf,
+		},
+	)
+
+	pod, _ := New(&v1pod)
+
+	client := fake.NewSimpleClientset(&v1pod)
+
+	_, err := pod.KillType(client)
+
+	assert.EqualError(t, err, pod.Kind()+" "+pod.Name()+" does not have "+config.KillTypeLabelKey+" label")
+
+	v1pod = newPod(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey:    ident,
+			config.MtbfLabelKey:     mtbf,
+			config.KillTypeLabelKey: killMode,
+		},
+	)
+
+	client = fake.NewSimpleClientset(&v1pod)
+
+	kill, _ := pod.KillType(client)
+
+	assert.Equal(t, kill, killMode, "Unexpected kill value, got %d", kill)
+}
+
+func TestKillValue(t *testing.T) {
+
+	ident := "1"
+	mtbf := "1"
+	killValue := "0"
+
+	v1pod := newPod(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: ident,
+			config.MtbfLabelKey:  mtbf,
+		},
+	)
+
+	pod, _ := New(&v1pod)
+
+	client := fake.NewSimpleClientset(&v1pod)
+
+	_, err := pod.KillValue(client)
+
+	assert.EqualError(t, err, pod.Kind()+" "+pod.Name()+" does not have "+config.KillValueLabelKey+" label")
+
+	v1pod = newPod(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey:     ident,
+			config.MtbfLabelKey:      mtbf,
+			config.KillValueLabelKey: killValue,
+		},
+	)
+
+	client = fake.NewSimpleClientset(&v1pod)
+
+	_, err = pod.KillValue(client)
+
+	assert.EqualError(t, err, "Invalid value for label "+config.KillValueLabelKey+": "+killValue)
+
+	killValue = "1"
+
+	v1pod = newPod(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey:     ident,
+			config.MtbfLabelKey:      mtbf,
+			config.KillValueLabelKey: killValue,
+		},
+	)
+
+	client = fake.NewSimpleClientset(&v1pod)
+
+	kill, _ := pod.KillValue(client)
+
+	assert.Equalf(t, kill, 1, "Unexpected a kill value, got %d", kill)
+}
+
+func TestEligiblePodsSkipsControllerOwned(t *testing.T) {
+	v1pod := newPod(
+		NAME,
+		map[string]string{
+			"kube-monkey/identifier": "1",
+			"kube-monkey/mtbf":       "1",
+		},
+	)
+	isController := true
+	v1pod.OwnerReferences = []metav1.OwnerReference{
+		{
+			APIVersion: "apps/v1",
+			Kind:       "ReplicaSet",
+			Name:       "replicaset_name",
+			Controller: &isController,
+		},
+	}
+
+	client := fake.NewSimpleClientset(&v1pod)
+	victims, _ := EligiblePods(client, NAMESPACE, &metav1.ListOptions{})
+
+	assert.Len(t, victims, 0)
+}
+
+func TestRunningPodsOnlyTargetsItself(t *testing.T) {
+	labels := map[string]string{
+		config.IdentLabelKey: IDENTIFIER,
+		config.MtbfLabelKey:  "1",
+	}
+	v1pod := newPod(NAME, labels)
+	v1pod.UID = "uid-1"
+	v1pod.Status.Phase = corev1.PodRunning
+	pod, _ := New(&v1pod)
+
+	// Another bare pod and a pod of a workload share the identifier
+	other := newPod("other", labels)
+	other.UID = "uid-2"
+	other.Status.Phase = corev1.PodRunning
+	owned := newPod("owned", labels)
+	owned.UID = "uid-3"
+	owned.Status.Phase = corev1.PodRunning
+	owned.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "app"}}
+	client := fake.NewSimpleClientset(&v1pod, &other, &owned)
+
+	pods, err := pod.RunningPods(client)
+	assert.NoError(t, err)
+	if assert.Len(t, pods, 1) {
+		assert.Equal(t, NAME, pods[0].Name)
+	}
+
+	// A pod recreated with the same name is another pod
+	recreated := v1pod
+	recreated.UID = "uid-4"
+	client = fake.NewSimpleClientset(&recreated, &other)
+
+	pods, err = pod.RunningPods(client)
+	assert.NoError(t, err)
+	assert.Empty(t, pods)
+}
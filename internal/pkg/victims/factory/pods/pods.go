@@ -0,0 +1,45 @@
+package pods
+
+import (
+	"fmt"
+
+	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/victims"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+type Pod struct {
+	*victims.VictimBase
+}
+
+// New creates a new instance of Pod
+// The victim stands for the pod itself, found by its name and UID, so that
+// other pods sharing its identifier label are never terminated
+func New(pod *corev1.Pod) (*Pod, error) {
+	mtbf, err := meanTimeBetweenFailures(pod)
+	if err != nil {
+		return nil, err
+	}
+	kind := fmt.Sprintf("%T", *pod)
+
+	// The identifier label is optional, and only used by network isolation
+	victim := victims.New(kind, pod.Name, pod.Namespace, pod.Labels[config.IdentLabelKey], mtbf)
+	victim.SetPodUID(pod.UID)
+	if err := victim.ConfigureSettings(pod, nil); err != nil {
+		return nil, err
+	}
+
+	return &Pod{VictimBase: victim}, nil
+}
+
+// Read the mean-time-between-failures value defined by the Pod
+// in the label defined by config.MtbfLabelKey
+func meanTimeBetweenFailures(kubekind *corev1.Pod) (float64, error) {
+	mtbf, ok := kubekind.Labels[config.MtbfLabelKey]
+	if !ok {
+		return -1, fmt.Errorf("%T %s does not have %s label", kubekind, kubekind.Name, config.MtbfLabelKey)
+	}
+
+	return victims.ParseMtbf(mtbf)
+}
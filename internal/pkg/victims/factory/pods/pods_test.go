@@ -0,0 +1,93 @@
+package pods
+
+import (
+	"testing"
+
+	"kube-monkey/internal/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	IDENTIFIER = "kube-monkey-id"
+	NAME       = "pod_name"
+	NAMESPACE  = metav1.NamespaceDefault
+)
+
+func newPod(name string, labels map[string]string) corev1.Pod {
+
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: NAMESPACE,
+			Labels:    labels,
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+
+	v1pod := newPod(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: IDENTIFIER,
+			config.MtbfLabelKey:  "1",
+		},
+	)
+	pod, err := New(&v1pod)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.Pod", pod.Kind())
+	assert.Equal(t, NAME, pod.Name())
+	assert.Equal(t, NAMESPACE, pod.Namespace())
+	assert.Equal(t, IDENTIFIER, pod.Identifier())
+	assert.Equal(t, float64(1), pod.Mtbf())
+}
+
+func TestNewWithoutIdentifier(t *testing.T) {
+	v1pod := newPod(
+		NAME,
+		map[string]string{
+			config.MtbfLabelKey: "1",
+		},
+	)
+	_, err := New(&v1pod)
+
+	assert.NoError(t, err, "Expected the pod to be found by its name without "+config.IdentLabelKey)
+}
+
+func TestInvalidMtbf(t *testing.T) {
+	v1pod := newPod(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: IDENTIFIER,
+		},
+	)
+	_, err := New(&v1pod)
+
+	assert.Errorf(t, err, "Expected an error if "+config.MtbfLabelKey+" label doesn't exist")
+
+	v1pod = newPod(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: IDENTIFIER,
+			config.MtbfLabelKey:  "string",
+		},
+	)
+	_, err = New(&v1pod)
+
+	assert.Errorf(t, err, "Expected an error if "+config.MtbfLabelKey+" label can't be converted a Int type")
+
+	v1pod = newPod(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: IDENTIFIER,
+			config.MtbfLabelKey:  "0",
+		},
+	)
+	_, err = New(&v1pod)
+
+	assert.Errorf(t, err, "Expected an error if "+config.MtbfLabelKey+" label is lower than 1")
+}
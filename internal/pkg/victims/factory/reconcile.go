@@ -0,0 +1,27 @@
+package factory
+
+import (
+	"kube-monkey/internal/pkg/victims"
+	"kube-monkey/internal/pkg/victims/factory/deployments"
+	"kube-monkey/internal/pkg/victims/factory/nodes"
+	"kube-monkey/internal/pkg/victims/factory/statefulsets"
+
+	kube "k8s.io/client-go/kubernetes"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// ReconcileAttacks reverts the temporary attacks that were interrupted by
+// a restart of kube-monkey, such as network isolations, readiness isolations,
+// scale downs, node drains and node taints
+// All kinds of attacks are reverted, even if some of them fail
+func ReconcileAttacks(clientset kube.Interface) error {
+	return utilerrors.NewAggregate([]error{
+		victims.ReconcileNetworkPolicies(clientset),
+		victims.ReconcileIsolatedPods(clientset),
+		deployments.RestoreReplicas(clientset),
+		statefulsets.RestoreReplicas(clientset),
+		nodes.ReconcileCordonedNodes(clientset),
+		nodes.ReconcileTaintedNodes(clientset),
+	})
+}
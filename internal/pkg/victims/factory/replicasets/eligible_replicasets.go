@@ -0,0 +1,117 @@
+package replicasets
+
+//All these functions require api access specific to the version of the app
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/golang/glog"
+
+	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/victims"
+
+	kube "k8s.io/client-go/kubernetes"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EligibleReplicaSets gets all eligible replicasets that opted in (filtered by config.EnabledLabel)
+// ReplicaSets managed by a Deployment are skipped, since their pods are
+// already reachable through the owning Deployment
+func EligibleReplicaSets(clientset kube.Interface, namespace string, filter *metav1.ListOptions) (eligVictims []victims.Victim, err error) {
+	enabledVictims, err := clientset.AppsV1().ReplicaSets(namespace).List(context.TODO(), *filter)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := victims.NewEnrolledNamespaces(clientset)
+	for _, vic := range enabledVictims.Items {
+		if ownedByDeployment(&vic) {
+			glog.V(5).Infof("Skipping eligible %T %s because it is owned by a Deployment", vic, vic.Name)
+			continue
+		}
+
+		ns, err := namespaces.Get(vic.Namespace)
+		if err != nil {
+			glog.Warningf("Skipping eligible %T %s because of error: %s", vic, vic.Name, err.Error())
+			continue
+		}
+
+		victim, err := New(&vic, ns)
+		if err != nil {
+			glog.Warningf("Skipping eligible %T %s because of error: %s", vic, vic.Name, err.Error())
+			continue
+		}
+
+		if victim.IsBlacklisted(clientset) {
+			continue
+		}
+
+		eligVictims = append(eligVictims, victim)
+	}
+
+	return
+}
+
+// Checks if the replicaset is controlled by a Deployment
+func ownedByDeployment(rs *appsv1.ReplicaSet) bool {
+	owner := metav1.GetControllerOf(rs)
+	return owner != nil && owner.Kind == "Deployment"
+}
+
+/* Below methods are used to verify the victim's attributes have not changed at the scheduled time of termination */
+
+// IsEnrolled checks if the replicaset is currently enrolled in kube-monkey
+func (r *ReplicaSet) IsEnrolled(clientset kube.Interface) (bool, error) {
+	replicaset, err := clientset.AppsV1().ReplicaSets(r.Namespace()).Get(context.TODO(), r.Name(), metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+	return victims.IsEnrolled(clientset, replicaset)
+}
+
+// KillType returns current killtype config setting for update
+func (r *ReplicaSet) KillType(clientset kube.Interface) (string, error) {
+	replicaset, err := clientset.AppsV1().ReplicaSets(r.Namespace()).Get(context.TODO(), r.Name(), metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	ns, err := victims.EnrolledNamespace(clientset, replicaset.Namespace)
+	if err != nil {
+		return "", err
+	}
+
+	killType, ok := victims.SettingOrDefault(replicaset, ns, config.KillTypeLabelKey)
+	if !ok {
+		return "", fmt.Errorf("%s %s does not have %s label or annotation", r.Kind(), r.Name(), config.KillTypeLabelKey)
+	}
+
+	return killType, nil
+}
+
+// KillValue returns current killvalue config setting for update
+func (r *ReplicaSet) KillValue(clientset kube.Interface) (int, error) {
+	replicaset, err := clientset.AppsV1().ReplicaSets(r.Namespace()).Get(context.TODO(), r.Name(), metav1.GetOptions{})
+	if err != nil {
+		return -1, err
+	}
+	ns, err := victims.EnrolledNamespace(clientset, replicaset.Namespace)
+	if err != nil {
+		return -1, err
+	}
+
+	killMode, ok := victims.SettingOrDefault(replicaset, ns, config.KillValueLabelKey)
+	if !ok {
+		return -1, fmt.Errorf("%s %s does not have %s label or annotation", r.Kind(), r.Name(), config.KillValueLabelKey)
+	}
+
+	killModeInt, err := strconv.Atoi(killMode)
+	if err != nil || !(killModeInt > 0) {
+		return -1, fmt.Errorf("Invalid value for label %s: %d", config.KillValueLabelKey, killModeInt)
+	}
+
+	return killModeInt, nil
+}
@@ -0,0 +1,181 @@
+package replicasets
+
+import (
+	"testing"
+
+	"kube-monkey/internal/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEligibleReplicaSets(t *testing.T) {
+	v1rs := newReplicaSet(
+		NAME,
+		map[string]string{
+			"kube-monkey/identifier": "1",
+			"kube-monkey/mtbf":       "1",
+		},
+	)
+
+	client := fake.NewSimpleClientset(&v1rs)
+	victims, _ := EligibleReplicaSets(client, NAMESPACE, &metav1.ListOptions{})
+
+	assert.Len(t, victims, 1)
+}
+
+func TestIsEnrolled(t *testing.T) {
+	v1rs := newReplicaSet(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey:   "1",
+			config.MtbfLabelKey:    "1",
+			config.EnabledLabelKey: config.EnabledLabelValue,
+		},
+	)
+
+	rs, _ := New(&v1rs, nil)
+
+	client := fake.NewSimpleClientset(&v1rs)
+
+	b, _ := rs.IsEnrolled(client)
+
+	assert.Equal(t, b, true, "Expected replicaset to be enrolled")
+}
+
+func TestIsNotEnrolled(t *testing.T) {
+	v1rs := newReplicaSet(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey:   "1",
+			config.MtbfLabelKey:    "1",
+			config.EnabledLabelKey: "x",
+		},
+	)
+
+	rs, _ := New(&v1rs, nil)
+
+	client := fake.NewSimpleClientset(&v1rs)
+
+	b, _ := rs.IsEnrolled(client)
+
+	assert.Equal(t, b, false, "Expected replicaset to not be enrolled")
+}
+
+func TestKillType(t *testing.T) {
+
+	ident := "1"
+	mtbf := "1"
+	killMode := "kill-mode"
+
+	v1rs := newReplicaSet(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: ident,
+			config.MtbfLabelKey:  mtbf,
+		},
+	)
+
+	rs, _ := New(&v1rs, nil)
+
+	client := fake.NewSimpleClientset(&v1rs)
+
+	_, err := rs.KillType(client)
+
+	assert.EqualError(t, err, rs.Kind()+" "+rs.Name()+" does not have "+config.KillTypeLabelKey+" label or annotation")
+
+	v1rs = newReplicaSet(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey:    ident,
+			config.MtbfLabelKey:     mtbf,
+			config.KillTypeLabelKey: killMode,
+		},
+	)
+
+	client = fake.NewSimpleClientset(&v1rs)
+
+	kill, _ := rs.KillType(client)
+
+	assert.Equal(t, kill, killMode, "Unexpected kill value, got %d", kill)
+}
+
+func TestKillValue(t *testing.T) {
+
+	ident := "1"
+	mtbf := "1"
+	killValue := "0"
+
+	v1rs := newReplicaSet(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: ident,
+			config.MtbfLabelKey:  mtbf,
+		},
+	)
+
+	rs, _ := New(&v1rs, nil)
+
+	client := fake.NewSimpleClientset(&v1rs)
+
+	_, err := rs.KillValue(client)
+
+	assert.EqualError(t, err, rs.Kind()+" "+rs.Name()+" does not have "+config.KillValueLabelKey+" label or annotation")
+
+	v1rs = newReplicaSet(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey:     ident,
+			config.MtbfLabelKey:      mtbf,
+			config.KillValueLabelKey: killValue,
+		},
+	)
+
+	client = fake.NewSimpleClientset(&v1rs)
+
+	_, err = rs.KillValue(client)
+
+	assert.EqualError(t, err, "Invalid value for label "+config.KillValueLabelKey+": "+killValue)
+
+	killValue = "1"
+
+	v1rs = newReplicaSet(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey:     ident,
+			config.MtbfLabelKey:      mtbf,
+			config.KillValueLabelKey: killValue,
+		},
+	)
+
+	client = fake.NewSimpleClientset(&v1rs)
+
+	kill, _ := rs.KillValue(client)
+
+	assert.Equalf(t, kill, 1, "Unexpected a kill value, got %d", kill)
+}
+
+func TestEligibleReplicaSetsSkipsDeploymentOwned(t *testing.T) {
+	v1rs := newReplicaSet(
+		NAME,
+		map[string]string{
+			"kube-monkey/identifier": "1",
+			"kube-monkey/mtbf":       "1",
+		},
+	)
+	isController := true
+	v1rs.OwnerReferences = []metav1.OwnerReference{
+		{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Name:       "deployment_name",
+			Controller: &isController,
+		},
+	}
+
+	client := fake.NewSimpleClientset(&v1rs)
+	victims, _ := EligibleReplicaSets(client, NAMESPACE, &metav1.ListOptions{})
+
+	assert.Len(t, victims, 0)
+}
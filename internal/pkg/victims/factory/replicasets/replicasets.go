@@ -0,0 +1,65 @@
+package replicasets
+
+import (
+	"fmt"
+
+	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/victims"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type ReplicaSet struct {
+	*victims.VictimBase
+}
+
+// New creates a new instance of ReplicaSet
+// ns is the namespace of the ReplicaSet if it is enrolled as a whole, and nil otherwise
+func New(rs *appsv1.ReplicaSet, ns *corev1.Namespace) (*ReplicaSet, error) {
+	ident, err := identifier(rs)
+	// The identifier label is optional when pods are found by their selector,
+	// which is always the case for workloads enrolled through their namespace
+	if err != nil && !config.PodDiscoveryBySelector() && ns == nil {
+		return nil, err
+	}
+	mtbf, err := meanTimeBetweenFailures(rs, ns)
+	if err != nil {
+		return nil, err
+	}
+	kind := fmt.Sprintf("%T", *rs)
+
+	victim := victims.New(kind, rs.Name, rs.Namespace, ident, mtbf)
+	if err := victim.ConfigurePodDiscovery(rs.Spec.Selector, rs.UID); err != nil {
+		return nil, err
+	}
+	if err := victim.ConfigureSettings(rs, ns); err != nil {
+		return nil, err
+	}
+
+	return &ReplicaSet{VictimBase: victim}, nil
+}
+
+// Returns the value of the setting defined by config.IdentLabelKey
+// from the replicaset annotations or labels
+// This label should be unique to a replicaset, and is used to
+// identify the pods that belong to this replicaset, as pods
+// inherit labels from the ReplicaSet
+func identifier(kubekind *appsv1.ReplicaSet) (string, error) {
+	identifier, ok := victims.Setting(kubekind, config.IdentLabelKey)
+	if !ok {
+		return "", fmt.Errorf("%T %s does not have %s label or annotation", kubekind, kubekind.Name, config.IdentLabelKey)
+	}
+	return identifier, nil
+}
+
+// Read the mean-time-between-failures value defined by the ReplicaSet
+// in the setting defined by config.MtbfLabelKey, or the default of its namespace
+func meanTimeBetweenFailures(kubekind *appsv1.ReplicaSet, ns *corev1.Namespace) (float64, error) {
+	mtbf, ok := victims.SettingOrDefault(kubekind, ns, config.MtbfLabelKey)
+	if !ok {
+		return -1, fmt.Errorf("%T %s does not have %s label or annotation", kubekind, kubekind.Name, config.MtbfLabelKey)
+	}
+
+	return victims.ParseMtbf(mtbf)
+}
@@ -1,13 +1,20 @@
 package statefulsets
 
 import (
+	"context"
 	"testing"
 
 	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/config/param"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 func TestEligibleStatefulSets(t *testing.T) {
@@ -35,7 +42,7 @@ func TestIsEnrolled(t *testing.T) {
 		},
 	)
 
-	stfs, _ := New(&v1stfs)
+	stfs, _ := New(&v1stfs, nil)
 
 	client := fake.NewSimpleClientset(&v1stfs)
 
@@ -54,7 +61,7 @@ func TestIsNotEnrolled(t *testing.T) {
 		},
 	)
 
-	stfs, _ := New(&v1stfs)
+	stfs, _ := New(&v1stfs, nil)
 
 	client := fake.NewSimpleClientset(&v1stfs)
 
@@ -77,13 +84,13 @@ func TestKillType(t *testing.T) {
 		},
 	)
 
-	stfs, _ := New(&v1stfs)
+	stfs, _ := New(&v1stfs, nil)
 
 	client := fake.NewSimpleClientset(&v1stfs)
 
 	_, err := stfs.KillType(client)
 
-	assert.EqualError(t, err, stfs.Kind()+" "+stfs.Name()+" does not have "+config.KillTypeLabelKey+" label")
+	assert.EqualError(t, err, stfs.Kind()+" "+stfs.Name()+" does not have "+config.KillTypeLabelKey+" label or annotation")
 
 	v1stfs = newStatefulSet(
 		NAME,
@@ -115,13 +122,13 @@ func TestKillValue(t *testing.T) {
 		},
 	)
 
-	stfs, _ := New(&v1stfs)
+	stfs, _ := New(&v1stfs, nil)
 
 	client := fake.NewSimpleClientset(&v1stfs)
 
 	_, err := stfs.KillValue(client)
 
-	assert.EqualError(t, err, stfs.Kind()+" "+stfs.Name()+" does not have "+config.KillValueLabelKey+" label")
+	assert.EqualError(t, err, stfs.Kind()+" "+stfs.Name()+" does not have "+config.KillValueLabelKey+" label or annotation")
 
 	v1stfs = newStatefulSet(
 		NAME,
@@ -155,3 +162,148 @@ func TestKillValue(t *testing.T) {
 
 	assert.Equalf(t, kill, 1, "Unexpected a kill value, got %d", kill)
 }
+
+func TestSettingsFromAnnotations(t *testing.T) {
+	v1stfs := newStatefulSet(
+		NAME,
+		map[string]string{
+			config.EnabledLabelKey:  config.EnabledLabelValue,
+			config.KillTypeLabelKey: config.KillFixedLabelValue,
+		},
+	)
+	v1stfs.Annotations = map[string]string{
+		config.IdentLabelKey:     "1",
+		config.MtbfLabelKey:      "2",
+		config.KillTypeLabelKey:  config.KillFixedPercentageLabelValue,
+		config.KillValueLabelKey: "50",
+	}
+
+	stfs, err := New(&v1stfs, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", stfs.Identifier())
+	assert.Equal(t, float64(2), stfs.Mtbf())
+
+	client := fake.NewSimpleClientset(&v1stfs)
+
+	killType, _ := stfs.KillType(client)
+	assert.Equal(t, config.KillFixedPercentageLabelValue, killType, "Expected the annotation to take precedence over the label")
+
+	killValue, _ := stfs.KillValue(client)
+	assert.Equal(t, 50, killValue)
+}
+
+func TestReplicas(t *testing.T) {
+	v1stfs := newStatefulSet(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey: "1",
+			config.MtbfLabelKey:  "1",
+		},
+	)
+	stfs, _ := New(&v1stfs, nil)
+
+	v1stfs.Status.ReadyReplicas = 0
+	client := fake.NewSimpleClientset(&v1stfs)
+
+	ready, desired, err := stfs.Replicas(client)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), ready)
+	assert.Equal(t, int32(1), desired, "Expected 1 replica if not specified")
+}
+
+// Serves the scale subresource of the statefulsets from their spec, which the
+// fake clientset does not do by itself
+func scaleReactor(client *fake.Clientset) k8stesting.ReactionFunc {
+	gvr := appsv1.SchemeGroupVersion.WithResource("statefulsets")
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "scale" {
+			return false, nil, nil
+		}
+
+		obj, err := client.Tracker().Get(gvr, action.GetNamespace(), NAME)
+		if err != nil {
+			return true, nil, err
+		}
+		workload := obj.(*appsv1.StatefulSet).DeepCopy()
+
+		if update, ok := action.(k8stesting.UpdateAction); ok {
+			scale := update.GetObject().(*autoscalingv1.Scale)
+			workload.Spec.Replicas = &scale.Spec.Replicas
+			return true, scale, client.Tracker().Update(gvr, workload, workload.Namespace)
+		}
+		return true, &autoscalingv1.Scale{
+			ObjectMeta: metav1.ObjectMeta{Name: workload.Name, Namespace: workload.Namespace},
+			Spec:       autoscalingv1.ScaleSpec{Replicas: *workload.Spec.Replicas},
+		}, nil
+	}
+}
+
+func TestScaleDown(t *testing.T) {
+	defer viper.Set(param.DryRun, viper.GetBool(param.DryRun))
+	viper.Set(param.DryRun, false)
+
+	v1ss := newStatefulSet(
+		NAME,
+		map[string]string{
+			config.IdentLabelKey:          "1",
+			config.MtbfLabelKey:           "1",
+			config.AttackDurationLabelKey: "1ms",
+		},
+	)
+	ss, _ := New(&v1ss, nil)
+
+	replicas := int32(3)
+	v1ss.Spec.Replicas = &replicas
+	client := fake.NewSimpleClientset(&v1ss)
+	reactor := scaleReactor(client)
+	var scaledTo []int32
+	client.PrependReactor("update", "statefulsets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() == "scale" {
+			scaledTo = append(scaledTo, action.(k8stesting.UpdateAction).GetObject().(*autoscalingv1.Scale).Spec.Replicas)
+		}
+		return reactor(action)
+	})
+	client.PrependReactor("get", "statefulsets", reactor)
+
+	outcome, err := ss.ScaleDown(client, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Scaled down from 3 to 2 replicas for 1ms", outcome)
+	assert.Equal(t, []int32{2, 3}, scaledTo)
+
+	updated, _ := client.AppsV1().StatefulSets(NAMESPACE).Get(context.TODO(), NAME, metav1.GetOptions{})
+	assert.Equal(t, int32(3), *updated.Spec.Replicas)
+	assert.NotContains(t, updated.Annotations, config.OriginalReplicasAnnotationKey)
+}
+
+func TestRestoreReplicas(t *testing.T) {
+	v1ss := newStatefulSet(NAME, map[string]string{})
+	replicas := int32(1)
+	v1ss.Spec.Replicas = &replicas
+	v1ss.Annotations = map[string]string{config.OriginalReplicasAnnotationKey: "3"}
+	client := fake.NewSimpleClientset(&v1ss)
+	client.PrependReactor("*", "statefulsets", scaleReactor(client))
+
+	assert.NoError(t, RestoreReplicas(client))
+
+	restored, _ := client.AppsV1().StatefulSets(NAMESPACE).Get(context.TODO(), NAME, metav1.GetOptions{})
+	assert.Equal(t, int32(3), *restored.Spec.Replicas)
+	assert.NotContains(t, restored.Annotations, config.OriginalReplicasAnnotationKey)
+}
+
+func TestRestoreReplicasContinuesAfterFailure(t *testing.T) {
+	broken := newStatefulSet("broken", map[string]string{})
+	broken.Annotations = map[string]string{config.OriginalReplicasAnnotationKey: "three"}
+	v1ss := newStatefulSet(NAME, map[string]string{})
+	replicas := int32(1)
+	v1ss.Spec.Replicas = &replicas
+	v1ss.Annotations = map[string]string{config.OriginalReplicasAnnotationKey: "3"}
+	client := fake.NewSimpleClientset(&broken, &v1ss)
+	client.PrependReactor("*", "statefulsets", scaleReactor(client))
+
+	err := RestoreReplicas(client)
+	assert.EqualError(t, err, "Failed to restore replicas of StatefulSet "+NAMESPACE+"/broken: Invalid value for annotation "+config.OriginalReplicasAnnotationKey+": three")
+
+	restored, _ := client.AppsV1().StatefulSets(NAMESPACE).Get(context.TODO(), NAME, metav1.GetOptions{})
+	assert.Equal(t, int32(3), *restored.Spec.Replicas, "Expected the other statefulset to be restored")
+	assert.NotContains(t, restored.Annotations, config.OriginalReplicasAnnotationKey)
+}
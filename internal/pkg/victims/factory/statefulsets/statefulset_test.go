@@ -36,14 +36,14 @@ func TestNew(t *testing.T) {
 			config.MtbfLabelKey:  "1",
 		},
 	)
-	stfs, err := New(&v1stfs)
+	stfs, err := New(&v1stfs, nil)
 
 	assert.NoError(t, err)
 	assert.Equal(t, "v1.StatefulSet", stfs.Kind())
 	assert.Equal(t, NAME, stfs.Name())
 	assert.Equal(t, NAMESPACE, stfs.Namespace())
 	assert.Equal(t, IDENTIFIER, stfs.Identifier())
-	assert.Equal(t, 1, stfs.Mtbf())
+	assert.Equal(t, float64(1), stfs.Mtbf())
 }
 
 func TestInvalidIdentifier(t *testing.T) {
@@ -53,7 +53,7 @@ func TestInvalidIdentifier(t *testing.T) {
 			config.MtbfLabelKey: "1",
 		},
 	)
-	_, err := New(&v1stfs)
+	_, err := New(&v1stfs, nil)
 
 	assert.Errorf(t, err, "Expected an error if "+config.IdentLabelKey+" label doesn't exist")
 }
@@ -65,7 +65,7 @@ func TestInvalidMtbf(t *testing.T) {
 			config.IdentLabelKey: IDENTIFIER,
 		},
 	)
-	_, err := New(&v1stfs)
+	_, err := New(&v1stfs, nil)
 
 	assert.Errorf(t, err, "Expected an error if "+config.MtbfLabelKey+" label doesn't exist")
 
@@ -76,7 +76,7 @@ func TestInvalidMtbf(t *testing.T) {
 			config.MtbfLabelKey:  "string",
 		},
 	)
-	_, err = New(&v1stfs)
+	_, err = New(&v1stfs, nil)
 
 	assert.Errorf(t, err, "Expected an error if "+config.MtbfLabelKey+" label can't be converted a Int type")
 
@@ -87,7 +87,7 @@ func TestInvalidMtbf(t *testing.T) {
 			config.MtbfLabelKey:  "0",
 		},
 	)
-	_, err = New(&v1stfs)
+	_, err = New(&v1stfs, nil)
 
 	assert.Errorf(t, err, "Expected an error if "+config.MtbfLabelKey+" label is lower than 1")
 }
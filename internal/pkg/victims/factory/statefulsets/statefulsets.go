@@ -2,12 +2,12 @@ package statefulsets
 
 import (
 	"fmt"
-	"strconv"
 
 	"kube-monkey/internal/pkg/config"
 	"kube-monkey/internal/pkg/victims"
 
-	corev1 "k8s.io/api/apps/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 )
 
 type StatefulSet struct {
@@ -15,49 +15,51 @@ type StatefulSet struct {
 }
 
 // New creates a new instance of StatefulSet
-func New(ss *corev1.StatefulSet) (*StatefulSet, error) {
+// ns is the namespace of the StatefulSet if it is enrolled as a whole, and nil otherwise
+func New(ss *appsv1.StatefulSet, ns *corev1.Namespace) (*StatefulSet, error) {
 	ident, err := identifier(ss)
-	if err != nil {
+	// The identifier label is optional when pods are found by their selector,
+	// which is always the case for workloads enrolled through their namespace
+	if err != nil && !config.PodDiscoveryBySelector() && ns == nil {
 		return nil, err
 	}
-	mtbf, err := meanTimeBetweenFailures(ss)
+	mtbf, err := meanTimeBetweenFailures(ss, ns)
 	if err != nil {
 		return nil, err
 	}
 	kind := fmt.Sprintf("%T", *ss)
 
-	return &StatefulSet{VictimBase: victims.New(kind, ss.Name, ss.Namespace, ident, mtbf)}, nil
+	victim := victims.New(kind, ss.Name, ss.Namespace, ident, mtbf)
+	if err := victim.ConfigurePodDiscovery(ss.Spec.Selector, ss.UID); err != nil {
+		return nil, err
+	}
+	if err := victim.ConfigureSettings(ss, ns); err != nil {
+		return nil, err
+	}
+
+	return &StatefulSet{VictimBase: victim}, nil
 }
 
-// Returns the value of the label defined by config.IdentLabelKey
-// from the statefulset labels
+// Returns the value of the setting defined by config.IdentLabelKey
+// from the statefulset annotations or labels
 // This label should be unique to a statefulset, and is used to
 // identify the pods that belong to this statefulset, as pods
 // inherit labels from the StatefulSet
-func identifier(kubekind *corev1.StatefulSet) (string, error) {
-	identifier, ok := kubekind.Labels[config.IdentLabelKey]
+func identifier(kubekind *appsv1.StatefulSet) (string, error) {
+	identifier, ok := victims.Setting(kubekind, config.IdentLabelKey)
 	if !ok {
-		return "", fmt.Errorf("%T %s does not have %s label", kubekind, kubekind.Name, config.IdentLabelKey)
+		return "", fmt.Errorf("%T %s does not have %s label or annotation", kubekind, kubekind.Name, config.IdentLabelKey)
 	}
 	return identifier, nil
 }
 
 // Read the mean-time-between-failures value defined by the StatefulSet
-// in the label defined by config.MtbfLabelKey
-func meanTimeBetweenFailures(kubekind *corev1.StatefulSet) (int, error) {
-	mtbf, ok := kubekind.Labels[config.MtbfLabelKey]
+// in the setting defined by config.MtbfLabelKey, or the default of its namespace
+func meanTimeBetweenFailures(kubekind *appsv1.StatefulSet, ns *corev1.Namespace) (float64, error) {
+	mtbf, ok := victims.SettingOrDefault(kubekind, ns, config.MtbfLabelKey)
 	if !ok {
-		return -1, fmt.Errorf("%T %s does not have %s label", kubekind, kubekind.Name, config.MtbfLabelKey)
-	}
-
-	mtbfInt, err := strconv.Atoi(mtbf)
-	if err != nil {
-		return -1, err
-	}
-
-	if !(mtbfInt > 0) {
-		return -1, fmt.Errorf("Invalid value for label %s: %d", config.MtbfLabelKey, mtbfInt)
+		return -1, fmt.Errorf("%T %s does not have %s label or annotation", kubekind, kubekind.Name, config.MtbfLabelKey)
 	}
 
-	return mtbfInt, nil
+	return victims.ParseMtbf(mtbf)
 }
@@ -0,0 +1,91 @@
+package victims
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"kube-monkey/internal/pkg/config"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KillWindow is the time range during the day in which a victim can be killed
+type KillWindow struct {
+	StartHour int
+	EndHour   int
+	Location  *time.Location
+}
+
+// KillWindow returns the kill window of the victim, or nil if the
+// victim uses the global config.StartHour, config.EndHour and config.Timezone
+func (v *VictimBase) KillWindow() *KillWindow {
+	return v.killWindow
+}
+
+// SetKillWindow sets the kill window of the victim
+func (v *VictimBase) SetKillWindow(window *KillWindow) {
+	v.killWindow = window
+}
+
+// ConfigureKillWindow sets the kill window of the victim from the
+// settings of the workload, or the defaults of its enrolled namespace ns
+func (v *VictimBase) ConfigureKillWindow(obj metav1.Object, ns *corev1.Namespace) error {
+	window, err := ParseKillWindow(obj, ns)
+	if err != nil {
+		return err
+	}
+	v.SetKillWindow(window)
+	return nil
+}
+
+// ParseKillWindow reads the kill window defined by the config.StartHourLabelKey,
+// config.EndHourLabelKey and config.TimezoneLabelKey settings of a workload
+// Settings that are not defined fall back to the global config
+// Returns nil if the workload defines none of them
+func ParseKillWindow(obj metav1.Object, ns *corev1.Namespace) (*KillWindow, error) {
+	startHour, hasStart := SettingOrDefault(obj, ns, config.StartHourLabelKey)
+	endHour, hasEnd := SettingOrDefault(obj, ns, config.EndHourLabelKey)
+	timezone, hasTimezone := SettingOrDefault(obj, ns, config.TimezoneLabelKey)
+	if !hasStart && !hasEnd && !hasTimezone {
+		return nil, nil
+	}
+
+	window := &KillWindow{
+		StartHour: config.StartHour(),
+		EndHour:   config.EndHour(),
+		Location:  config.Timezone(),
+	}
+
+	var err error
+	if hasStart {
+		if window.StartHour, err = parseHour(config.StartHourLabelKey, startHour); err != nil {
+			return nil, err
+		}
+	}
+	if hasEnd {
+		if window.EndHour, err = parseHour(config.EndHourLabelKey, endHour); err != nil {
+			return nil, err
+		}
+	}
+	if hasTimezone {
+		if window.Location, err = time.LoadLocation(timezone); err != nil {
+			return nil, fmt.Errorf("Invalid value for label %s: %s", config.TimezoneLabelKey, timezone)
+		}
+	}
+
+	if !(window.StartHour < window.EndHour) {
+		return nil, fmt.Errorf("%s %s must be less than %s", obj.GetName(), config.StartHourLabelKey, config.EndHourLabelKey)
+	}
+
+	return window, nil
+}
+
+func parseHour(key, value string) (int, error) {
+	hour, err := strconv.Atoi(value)
+	if err != nil || !config.IsValidHour(hour) {
+		return -1, fmt.Errorf("Invalid value for label %s: %s", key, value)
+	}
+	return hour, nil
+}
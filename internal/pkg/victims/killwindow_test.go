@@ -0,0 +1,91 @@
+package victims
+
+import (
+	"testing"
+	"time"
+
+	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/config/param"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// config.SetDefaults would enable dry runs for the other tests
+func setKillWindowDefaults() {
+	viper.Set(param.StartHour, 10)
+	viper.Set(param.EndHour, 16)
+	viper.Set(param.Timezone, "UTC")
+}
+
+func resetKillWindowDefaults() {
+	viper.Set(param.StartHour, 0)
+	viper.Set(param.EndHour, 0)
+	viper.Set(param.Timezone, "")
+}
+
+func TestParseKillWindow(t *testing.T) {
+	setKillWindowDefaults()
+	defer resetKillWindowDefaults()
+
+	pod := newPod("app", corev1.PodRunning)
+	window, err := ParseKillWindow(&pod, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, window, "Expected no window without settings")
+
+	pod.Labels[config.StartHourLabelKey] = "9"
+	pod.Annotations = map[string]string{
+		config.TimezoneLabelKey: "Europe/Berlin",
+	}
+	window, err = ParseKillWindow(&pod, nil)
+	assert.NoError(t, err)
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	assert.Equal(t, &KillWindow{StartHour: 9, EndHour: config.EndHour(), Location: berlin}, window)
+
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: NAMESPACE,
+			Annotations: map[string]string{
+				config.EndHourLabelKey: "12",
+			},
+		},
+	}
+	window, err = ParseKillWindow(&pod, ns)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, window.EndHour, "Expected the end hour default of the namespace")
+}
+
+func TestInvalidKillWindow(t *testing.T) {
+	setKillWindowDefaults()
+	defer resetKillWindowDefaults()
+
+	pod := newPod("app", corev1.PodRunning)
+	pod.Labels[config.StartHourLabelKey] = "24"
+	_, err := ParseKillWindow(&pod, nil)
+	assert.EqualError(t, err, "Invalid value for label "+config.StartHourLabelKey+": 24")
+
+	pod.Labels[config.StartHourLabelKey] = "17"
+	_, err = ParseKillWindow(&pod, nil)
+	assert.EqualError(t, err, "app "+config.StartHourLabelKey+" must be less than "+config.EndHourLabelKey)
+
+	pod.Labels[config.StartHourLabelKey] = "9"
+	pod.Annotations = map[string]string{
+		config.TimezoneLabelKey: "Mars/Olympus_Mons",
+	}
+	_, err = ParseKillWindow(&pod, nil)
+	assert.EqualError(t, err, "Invalid value for label "+config.TimezoneLabelKey+": Mars/Olympus_Mons")
+}
+
+func TestConfigureKillWindow(t *testing.T) {
+	setKillWindowDefaults()
+	defer resetKillWindowDefaults()
+
+	v := newVictimBase()
+	pod := newPod("app", corev1.PodRunning)
+	pod.Labels[config.EndHourLabelKey] = "20"
+
+	assert.NoError(t, v.ConfigureKillWindow(&pod, nil))
+	assert.Equal(t, 20, v.KillWindow().EndHour)
+}
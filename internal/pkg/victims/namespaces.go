@@ -0,0 +1,119 @@
+package victims
+
+import (
+	"context"
+
+	"kube-monkey/internal/pkg/config"
+
+	kube "k8s.io/client-go/kubernetes"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// EnrolledNamespace returns the namespace if it opted in to kube-monkey as a
+// whole through the config.EnabledLabelKey label, and nil otherwise
+func EnrolledNamespace(clientset kube.Interface, name string) (*corev1.Namespace, error) {
+	ns, err := clientset.CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if ns.Labels[config.EnabledLabelKey] != config.EnabledLabelValue {
+		return nil, nil
+	}
+	return ns, nil
+}
+
+// EnrolledNamespaces looks up the enrolled namespaces of workloads at
+// scheduling time, fetching each namespace only once
+type EnrolledNamespaces struct {
+	clientset  kube.Interface
+	namespaces map[string]*corev1.Namespace
+}
+
+// NewEnrolledNamespaces creates a new instance of EnrolledNamespaces
+func NewEnrolledNamespaces(clientset kube.Interface) *EnrolledNamespaces {
+	return &EnrolledNamespaces{clientset: clientset, namespaces: map[string]*corev1.Namespace{}}
+}
+
+// Get returns the namespace if it is enrolled, and nil otherwise
+func (e *EnrolledNamespaces) Get(name string) (*corev1.Namespace, error) {
+	if ns, ok := e.namespaces[name]; ok {
+		return ns, nil
+	}
+
+	ns, err := EnrolledNamespace(e.clientset, name)
+	if err != nil {
+		return nil, err
+	}
+	e.namespaces[name] = ns
+	return ns, nil
+}
+
+// SettingOrDefault returns the value of a kube-monkey setting of a workload
+// If the workload does not define it, the annotations of its enrolled
+// namespace supply the default. ns is nil if the namespace is not enrolled
+func SettingOrDefault(obj metav1.Object, ns *corev1.Namespace, key string) (string, bool) {
+	if value, ok := Setting(obj, key); ok {
+		return value, true
+	}
+	if ns == nil {
+		return "", false
+	}
+	value, ok := ns.Annotations[key]
+	return value, ok
+}
+
+// IsEnrolled checks if a workload is enrolled, either by its own
+// config.EnabledLabelKey label or, without that label, by its namespace
+func IsEnrolled(clientset kube.Interface, obj metav1.Object) (bool, error) {
+	// The label of the workload takes precedence, so any other value
+	// opts the workload out of an enrolled namespace
+	if value, ok := obj.GetLabels()[config.EnabledLabelKey]; ok {
+		return value == config.EnabledLabelValue, nil
+	}
+
+	ns, err := EnrolledNamespace(clientset, obj.GetNamespace())
+	if err != nil {
+		return false, err
+	}
+	return ns != nil, nil
+}
+
+// IsBlacklistedNamespace checks the namespace against config.BlacklistedNamespaces
+// and config.BlacklistedNamespaceSelector
+func IsBlacklistedNamespace(ns *corev1.Namespace) (bool, error) {
+	if config.BlacklistEnabled() && config.NamespaceMatches(config.BlacklistedNamespaces(), ns.Name) {
+		return true, nil
+	}
+
+	selector, err := config.BlacklistedNamespaceSelector()
+	if err != nil || selector == nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(ns.Labels)), nil
+}
+
+// IsWhitelistedNamespace checks the namespace against config.WhitelistedNamespaces
+// and config.WhitelistedNamespaceSelector. All namespaces are whitelisted
+// if neither is configured
+func IsWhitelistedNamespace(ns *corev1.Namespace) (bool, error) {
+	selector, err := config.WhitelistedNamespaceSelector()
+	if err != nil {
+		return false, err
+	}
+
+	if !config.WhitelistEnabled() {
+		return selector == nil || selector.Matches(labels.Set(ns.Labels)), nil
+	}
+	if config.NamespaceMatches(config.WhitelistedNamespaces(), ns.Name) {
+		return true, nil
+	}
+	return selector != nil && selector.Matches(labels.Set(ns.Labels)), nil
+}
@@ -0,0 +1,116 @@
+package victims
+
+import (
+	"testing"
+
+	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/config/param"
+
+	"github.com/spf13/viper"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newNamespace(labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   NAMESPACE,
+			Labels: labels,
+			Annotations: map[string]string{
+				config.KillTypeLabelKey: config.KillAllLabelValue,
+			},
+		},
+	}
+}
+
+func TestEnrolledNamespace(t *testing.T) {
+	ns, err := EnrolledNamespace(fake.NewSimpleClientset(), NAMESPACE)
+	assert.NoError(t, err)
+	assert.Nil(t, ns, "Expected a missing namespace not to be enrolled")
+
+	client := fake.NewSimpleClientset(newNamespace(nil))
+	ns, _ = EnrolledNamespace(client, NAMESPACE)
+	assert.Nil(t, ns)
+
+	client = fake.NewSimpleClientset(newNamespace(map[string]string{config.EnabledLabelKey: config.EnabledLabelValue}))
+	ns, _ = EnrolledNamespace(client, NAMESPACE)
+	assert.NotNil(t, ns)
+
+	namespaces := NewEnrolledNamespaces(client)
+	ns, _ = namespaces.Get(NAMESPACE)
+	assert.NotNil(t, ns)
+}
+
+func TestSettingOrDefault(t *testing.T) {
+	pod := newPod("app", corev1.PodRunning)
+	ns := newNamespace(map[string]string{config.EnabledLabelKey: config.EnabledLabelValue})
+
+	_, ok := SettingOrDefault(&pod, nil, config.KillTypeLabelKey)
+	assert.False(t, ok)
+
+	value, _ := SettingOrDefault(&pod, ns, config.KillTypeLabelKey)
+	assert.Equal(t, config.KillAllLabelValue, value, "Expected the default of the namespace")
+
+	pod.Labels[config.KillTypeLabelKey] = config.KillFixedLabelValue
+	value, _ = SettingOrDefault(&pod, ns, config.KillTypeLabelKey)
+	assert.Equal(t, config.KillFixedLabelValue, value, "Expected the workload to override the namespace")
+}
+
+func TestIsEnrolledByNamespace(t *testing.T) {
+	pod := newPod("app", corev1.PodRunning)
+	client := fake.NewSimpleClientset(newNamespace(map[string]string{config.EnabledLabelKey: config.EnabledLabelValue}))
+
+	enrolled, _ := IsEnrolled(client, &pod)
+	assert.True(t, enrolled, "Expected an unlabeled workload to be enrolled by its namespace")
+
+	pod.Labels[config.EnabledLabelKey] = "disabled"
+	enrolled, _ = IsEnrolled(client, &pod)
+	assert.False(t, enrolled, "Expected the workload label to opt out of the namespace")
+
+	enrolled, _ = IsEnrolled(fake.NewSimpleClientset(), &pod)
+	assert.False(t, enrolled)
+}
+
+func TestIsWhitelistedNamespace(t *testing.T) {
+	defer viper.Set(param.WhitelistedNamespaces, []string{metav1.NamespaceAll})
+	defer viper.Set(param.WhitelistedNamespaceSelector, "")
+
+	staging := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "staging", Labels: map[string]string{"env": "staging"}}}
+	other := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other"}}
+
+	viper.Set(param.WhitelistedNamespaces, []string{metav1.NamespaceAll})
+	viper.Set(param.WhitelistedNamespaceSelector, "")
+	whitelisted, _ := IsWhitelistedNamespace(other)
+	assert.True(t, whitelisted, "Expected all namespaces to be whitelisted by default")
+
+	viper.Set(param.WhitelistedNamespaceSelector, "env in (staging)")
+	whitelisted, _ = IsWhitelistedNamespace(staging)
+	assert.True(t, whitelisted)
+	whitelisted, _ = IsWhitelistedNamespace(other)
+	assert.False(t, whitelisted, "Expected the selector to restrict the whitelist")
+
+	viper.Set(param.WhitelistedNamespaces, []string{"other"})
+	whitelisted, _ = IsWhitelistedNamespace(other)
+	assert.True(t, whitelisted, "Expected listed namespaces to stay whitelisted")
+	whitelisted, _ = IsWhitelistedNamespace(staging)
+	assert.True(t, whitelisted, "Expected selected namespaces to be whitelisted in addition")
+
+	viper.Set(param.WhitelistedNamespaceSelector, "env in staging")
+	_, err := IsWhitelistedNamespace(staging)
+	assert.Error(t, err)
+}
+
+func TestIsBlacklistedNamespace(t *testing.T) {
+	defer viper.Set(param.BlacklistedNamespaces, []string{metav1.NamespaceSystem})
+	viper.Set(param.BlacklistedNamespaces, []string{"kube-*", "/.+-critical/"})
+
+	blacklisted, _ := IsBlacklistedNamespace(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-public"}})
+	assert.True(t, blacklisted)
+	blacklisted, _ = IsBlacklistedNamespace(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments-critical"}})
+	assert.True(t, blacklisted)
+	blacklisted, _ = IsBlacklistedNamespace(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: NAMESPACE}})
+	assert.False(t, blacklisted)
+}
@@ -0,0 +1,145 @@
+package victims
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"kube-monkey/internal/pkg/config"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+
+	kube "k8s.io/client-go/kubernetes"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Prefix of the NetworkPolicies isolating the pods of a victim
+const isolationPolicyPrefix = "kube-monkey-isolate-"
+
+// IsolatePods cuts the pods of the victim off from the network for its
+// attack duration, with a NetworkPolicy denying all ingress and egress
+// traffic of the pods. The NetworkPolicy is labeled as managed by
+// kube-monkey, so ReconcileNetworkPolicies removes it if kube-monkey
+// restarts during the attack
+// Bare pods are refused, as a NetworkPolicy selects pods by their labels,
+// which other pods may share, rather than by their name
+func (v *VictimBase) IsolatePods(clientset kube.Interface) (string, error) {
+	if v.podUID != "" {
+		return "", fmt.Errorf("%s %s is a bare pod, which cannot be isolated from the network without isolating other pods with the same labels", v.kind, v.name)
+	}
+
+	pods, err := v.RunningPods(clientset)
+	if err != nil {
+		return "", err
+	}
+	if len(pods) == 0 {
+		return "", fmt.Errorf("%s %s has no running pods at the moment", v.kind, v.name)
+	}
+
+	duration := v.AttackDuration()
+	if config.DryRun() {
+		glog.Infof("[DryRun Mode] Isolated %d pods of %s/%s for %s", len(pods), v.namespace, v.name, duration)
+		return fmt.Sprintf("Isolated %d pods for %s", len(pods), duration), nil
+	}
+
+	policy, err := v.isolationPolicy(clientset)
+	if err != nil {
+		return "", err
+	}
+
+	glog.V(6).Infof("Isolating %d pods of %s %s/%s for %s with NetworkPolicy %s", len(pods), v.kind, v.namespace, v.name, duration, policy.Name)
+	policy, err = clientset.NetworkingV1().NetworkPolicies(v.namespace).Create(context.TODO(), policy, metav1.CreateOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to isolate pods of %s %s", v.kind, v.name)
+	}
+
+	time.Sleep(duration)
+
+	err = clientset.NetworkingV1().NetworkPolicies(v.namespace).Delete(context.TODO(), policy.Name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return "", errors.Wrapf(err, "Failed to remove NetworkPolicy %s isolating pods of %s %s", policy.Name, v.kind, v.name)
+	}
+
+	return fmt.Sprintf("Isolated %d pods for %s", len(pods), duration), nil
+}
+
+// Creates the deny-all NetworkPolicy selecting the pods of the victim
+// A policy without any rules for both policy types denies all traffic
+func (v *VictimBase) isolationPolicy(clientset kube.Interface) (*networkingv1.NetworkPolicy, error) {
+	podSelector, err := v.podLabelSelector(clientset)
+	if err != nil {
+		return nil, err
+	}
+
+	// A truncated name has to end with an alphanumeric character again
+	name := isolationPolicyPrefix + v.name
+	if len(name) > 253 {
+		name = strings.TrimRight(name[:253], "-.")
+	}
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: v.namespace,
+			Labels: map[string]string{
+				config.ManagedByLabelKey:       config.ManagedByLabelValue,
+				config.IsolationPolicyLabelKey: config.IsolationPolicyLabelValue,
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: *podSelector,
+			PolicyTypes: []networkingv1.PolicyType{
+				networkingv1.PolicyTypeIngress,
+				networkingv1.PolicyTypeEgress,
+			},
+		},
+	}, nil
+}
+
+// Returns the selector for the pods of this victim, the pod selector if one
+// was set and the identifier label otherwise
+func (v *VictimBase) podLabelSelector(clientset kube.Interface) (*metav1.LabelSelector, error) {
+	selector, err := v.resolvePodSelector(clientset)
+	if err != nil {
+		return nil, err
+	}
+	if selector != nil {
+		return metav1.ParseToLabelSelector(selector.String())
+	}
+	return &metav1.LabelSelector{
+		MatchLabels: map[string]string{config.IdentLabelKey: v.identifier},
+	}, nil
+}
+
+// ReconcileNetworkPolicies removes the NetworkPolicies left behind by
+// network isolation attacks that were interrupted by a restart of kube-monkey
+// Only policies with both the config.IsolationPolicyLabelKey label and the
+// name prefix of kube-monkey are removed, as other tools may label their
+// policies as managed by kube-monkey too
+func ReconcileNetworkPolicies(clientset kube.Interface) error {
+	isolation := labels.SelectorFromSet(labels.Set{config.IsolationPolicyLabelKey: config.IsolationPolicyLabelValue})
+	policies, err := clientset.NetworkingV1().NetworkPolicies(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: isolation.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, policy := range policies.Items {
+		if !strings.HasPrefix(policy.Name, isolationPolicyPrefix) {
+			continue
+		}
+		glog.V(3).Infof("Removing NetworkPolicy %s/%s left behind by an interrupted network isolation", policy.Namespace, policy.Name)
+		err := clientset.NetworkingV1().NetworkPolicies(policy.Namespace).Delete(context.TODO(), policy.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package victims
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"kube-monkey/internal/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestIsolatePods(t *testing.T) {
+	v := newVictimBase()
+	duration := time.Millisecond
+	v.attackDuration = &duration
+	pod1 := newPod("app1", corev1.PodRunning)
+	pod2 := newPod("app2", corev1.PodRunning)
+
+	var created *networkingv1.NetworkPolicy
+	client := fake.NewSimpleClientset(&pod1, &pod2)
+	client.PrependReactor("create", "networkpolicies", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		created = action.(k8stesting.CreateAction).GetObject().(*networkingv1.NetworkPolicy)
+		return false, nil, nil
+	})
+
+	outcome, err := v.IsolatePods(client)
+	assert.NoError(t, err)
+	assert.Equal(t, "Isolated 2 pods for 1ms", outcome)
+
+	if assert.NotNil(t, created) {
+		assert.Equal(t, "kube-monkey-isolate-"+NAME, created.Name)
+		assert.Equal(t, config.ManagedByLabelValue, created.Labels[config.ManagedByLabelKey])
+		assert.Equal(t, config.IsolationPolicyLabelValue, created.Labels[config.IsolationPolicyLabelKey])
+		assert.Equal(t, map[string]string{config.IdentLabelKey: IDENTIFIER}, created.Spec.PodSelector.MatchLabels)
+		assert.ElementsMatch(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}, created.Spec.PolicyTypes)
+		assert.Empty(t, created.Spec.Ingress, "Expected no ingress to be allowed")
+		assert.Empty(t, created.Spec.Egress, "Expected no egress to be allowed")
+	}
+
+	policies, _ := client.NetworkingV1().NetworkPolicies(NAMESPACE).List(context.TODO(), metav1.ListOptions{})
+	assert.Empty(t, policies.Items, "Expected the NetworkPolicy to be removed after the attack")
+}
+
+func TestIsolatePodsRefusesBarePods(t *testing.T) {
+	v := newVictimBase()
+	v.SetPodUID("uid")
+	pod := newPod(NAME, corev1.PodRunning)
+	client := fake.NewSimpleClientset(&pod)
+
+	_, err := v.IsolatePods(client)
+	assert.EqualError(t, err, "Pod "+NAME+" is a bare pod, which cannot be isolated from the network without isolating other pods with the same labels")
+
+	policies, _ := client.NetworkingV1().NetworkPolicies(NAMESPACE).List(context.TODO(), metav1.ListOptions{})
+	assert.Empty(t, policies.Items, "Expected no NetworkPolicy to be created")
+}
+
+func TestIsolationPolicyName(t *testing.T) {
+	v := newVictimBase()
+	// Truncating the name to 253 characters leaves a trailing "-"
+	v.name = strings.Repeat("a", 252-len(isolationPolicyPrefix)) + "-b"
+
+	policy, err := v.isolationPolicy(fake.NewSimpleClientset())
+	assert.NoError(t, err)
+	assert.Equal(t, isolationPolicyPrefix+strings.Repeat("a", 252-len(isolationPolicyPrefix)), policy.Name)
+}
+
+func TestIsolatePodsWithSelector(t *testing.T) {
+	v := newVictimBase()
+	duration := time.Millisecond
+	v.attackDuration = &duration
+	v.SetPodSelector(labels.SelectorFromSet(labels.Set{"app": "foo"}))
+	pod := newPod("app", corev1.PodRunning)
+	pod.Labels["app"] = "foo"
+
+	var created *networkingv1.NetworkPolicy
+	client := fake.NewSimpleClientset(&pod)
+	client.PrependReactor("create", "networkpolicies", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		created = action.(k8stesting.CreateAction).GetObject().(*networkingv1.NetworkPolicy)
+		return false, nil, nil
+	})
+
+	_, err := v.IsolatePods(client)
+	assert.NoError(t, err)
+	if assert.NotNil(t, created) {
+		assert.Equal(t, map[string]string{"app": "foo"}, created.Spec.PodSelector.MatchLabels)
+	}
+}
+
+func TestIsolatePodsNoRunningPods(t *testing.T) {
+	v := newVictimBase()
+	pod := newPod("app", corev1.PodPending)
+	client := fake.NewSimpleClientset(&pod)
+
+	_, err := v.IsolatePods(client)
+	assert.EqualError(t, err, KIND+" "+NAME+" has no running pods at the moment")
+}
+
+func TestReconcileNetworkPolicies(t *testing.T) {
+	owned := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kube-monkey-isolate-app",
+			Namespace: NAMESPACE,
+			Labels: map[string]string{
+				config.ManagedByLabelKey:       config.ManagedByLabelValue,
+				config.IsolationPolicyLabelKey: config.IsolationPolicyLabelValue,
+			},
+		},
+	}
+	other := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "default-deny",
+			Namespace: NAMESPACE,
+		},
+	}
+	// Deployed by a chart that labels its resources as managed by kube-monkey
+	chart := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kube-monkey",
+			Namespace: NAMESPACE,
+			Labels:    map[string]string{config.ManagedByLabelKey: config.ManagedByLabelValue},
+		},
+	}
+	// Carries the label, but was not created by an attack
+	unprefixed := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "isolate-app",
+			Namespace: NAMESPACE,
+			Labels:    map[string]string{config.IsolationPolicyLabelKey: config.IsolationPolicyLabelValue},
+		},
+	}
+	client := fake.NewSimpleClientset(owned, other, chart, unprefixed)
+
+	assert.NoError(t, ReconcileNetworkPolicies(client))
+
+	policies, _ := client.NetworkingV1().NetworkPolicies(NAMESPACE).List(context.TODO(), metav1.ListOptions{})
+	var names []string
+	for _, policy := range policies.Items {
+		names = append(names, policy.Name)
+	}
+	assert.ElementsMatch(t, []string{"default-deny", "kube-monkey", "isolate-app"}, names)
+}
+
+func TestConfigureAttackDuration(t *testing.T) {
+	v := newVictimBase()
+	assert.Equal(t, config.AttackDuration(), v.AttackDuration())
+
+	pod := newPod("app", corev1.PodRunning)
+	pod.Labels[config.AttackDurationLabelKey] = "120"
+	assert.NoError(t, v.ConfigureAttackDuration(&pod, nil))
+	assert.Equal(t, 2*time.Minute, v.AttackDuration())
+
+	pod.Labels[config.AttackDurationLabelKey] = "10m"
+	assert.NoError(t, v.ConfigureAttackDuration(&pod, nil))
+	assert.Equal(t, 10*time.Minute, v.AttackDuration())
+
+	pod.Labels[config.AttackDurationLabelKey] = "0"
+	err := v.ConfigureAttackDuration(&pod, nil)
+	assert.EqualError(t, err, "Invalid value for label "+config.AttackDurationLabelKey+": 0")
+}
@@ -0,0 +1,114 @@
+package victims
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"kube-monkey/internal/pkg/config"
+
+	kube "k8s.io/client-go/kubernetes"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// PodSelection returns the strategy used to pick the pods of the victim to kill
+func (v *VictimBase) PodSelection() string {
+	if v.podSelection == "" {
+		return config.PodSelectionRandomLabelValue
+	}
+	return v.podSelection
+}
+
+// ConfigurePodSelection sets the strategy used to pick the pods of the victim
+// from the config.PodSelectionLabelKey setting of the workload, or the default
+// of its enrolled namespace ns
+func (v *VictimBase) ConfigurePodSelection(obj metav1.Object, ns *corev1.Namespace) error {
+	value, ok := SettingOrDefault(obj, ns, config.PodSelectionLabelKey)
+	if !ok {
+		return nil
+	}
+
+	switch value {
+	case config.PodSelectionRandomLabelValue,
+		config.PodSelectionOldestLabelValue,
+		config.PodSelectionNewestLabelValue,
+		config.PodSelectionMostRestartedLabelValue,
+		config.PodSelectionMostCrowdedNodeLabelValue:
+		v.podSelection = value
+		return nil
+	default:
+		return fmt.Errorf("Invalid value for label %s: %s", config.PodSelectionLabelKey, value)
+	}
+}
+
+// SelectPods picks killNum of the pods following the strategy of the victim
+// Each pod is picked at most once, so all pods are picked if there are
+// no more than killNum. Pods that rank equally are picked at random
+func (v *VictimBase) SelectPods(clientset kube.Interface, pods []corev1.Pod, killNum int) ([]corev1.Pod, error) {
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	selected := make([]corev1.Pod, len(pods))
+	for i, j := range r.Perm(len(pods)) {
+		selected[i] = pods[j]
+	}
+
+	switch v.PodSelection() {
+	case config.PodSelectionOldestLabelValue:
+		sort.SliceStable(selected, func(i, j int) bool {
+			return selected[i].CreationTimestamp.Before(&selected[j].CreationTimestamp)
+		})
+	case config.PodSelectionNewestLabelValue:
+		sort.SliceStable(selected, func(i, j int) bool {
+			return selected[j].CreationTimestamp.Before(&selected[i].CreationTimestamp)
+		})
+	case config.PodSelectionMostRestartedLabelValue:
+		sort.SliceStable(selected, func(i, j int) bool {
+			return restartCount(selected[i]) > restartCount(selected[j])
+		})
+	case config.PodSelectionMostCrowdedNodeLabelValue:
+		podsPerNode, err := podsPerNode(clientset, selected)
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(selected, func(i, j int) bool {
+			return podsPerNode[selected[i].Spec.NodeName] > podsPerNode[selected[j].Spec.NodeName]
+		})
+	}
+
+	if killNum < len(selected) {
+		selected = selected[:killNum]
+	}
+	return selected, nil
+}
+
+// Sums the restarts of all containers of the pod
+func restartCount(pod corev1.Pod) (restarts int32) {
+	for _, status := range pod.Status.ContainerStatuses {
+		restarts += status.RestartCount
+	}
+	return
+}
+
+// Counts the pods of all namespaces on each node hosting one of the pods
+func podsPerNode(clientset kube.Interface, pods []corev1.Pod) (map[string]int, error) {
+	counts := map[string]int{}
+	for _, pod := range pods {
+		node := pod.Spec.NodeName
+		if _, ok := counts[node]; ok || node == "" {
+			continue
+		}
+
+		nodePods, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector("spec.nodeName", node).String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		counts[node] = len(nodePods.Items)
+	}
+	return counts, nil
+}
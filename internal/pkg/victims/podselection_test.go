@@ -0,0 +1,113 @@
+package victims
+
+import (
+	"testing"
+	"time"
+
+	"kube-monkey/internal/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newScheduledPod(name, node string, age time.Duration, restarts int32) corev1.Pod {
+	pod := newPod(name, corev1.PodRunning)
+	pod.CreationTimestamp = metav1.NewTime(time.Now().Add(-age))
+	pod.Spec.NodeName = node
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{RestartCount: restarts}}
+	return pod
+}
+
+func podNames(pods []corev1.Pod) (names []string) {
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	return
+}
+
+func TestSelectPodsWithoutReplacement(t *testing.T) {
+	v := newVictimBase()
+	pods := []corev1.Pod{
+		newPod("app1", corev1.PodRunning),
+		newPod("app2", corev1.PodRunning),
+		newPod("app3", corev1.PodRunning),
+	}
+
+	selected, err := v.SelectPods(fake.NewSimpleClientset(), pods, 3)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"app1", "app2", "app3"}, podNames(selected), "Expected every pod to be picked once")
+
+	selected, _ = v.SelectPods(fake.NewSimpleClientset(), pods, 5)
+	assert.Len(t, selected, 3)
+
+	selected, _ = v.SelectPods(fake.NewSimpleClientset(), pods, 2)
+	assert.Len(t, selected, 2)
+	assert.NotEqual(t, selected[0].Name, selected[1].Name)
+}
+
+func TestSelectPodsStrategies(t *testing.T) {
+	pods := []corev1.Pod{
+		newScheduledPod("old", "node1", 3*time.Hour, 0),
+		newScheduledPod("new", "node2", time.Hour, 1),
+		newScheduledPod("restarted", "node2", 2*time.Hour, 5),
+	}
+
+	for strategy, expected := range map[string][]string{
+		config.PodSelectionOldestLabelValue:        {"old", "restarted"},
+		config.PodSelectionNewestLabelValue:        {"new", "restarted"},
+		config.PodSelectionMostRestartedLabelValue: {"restarted", "new"},
+	} {
+		v := newVictimBase()
+		v.podSelection = strategy
+		selected, err := v.SelectPods(fake.NewSimpleClientset(), pods, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, podNames(selected), "Unexpected pods for %s", strategy)
+	}
+}
+
+func TestSelectPodsOnMostCrowdedNode(t *testing.T) {
+	pods := []corev1.Pod{
+		newScheduledPod("alone", "node1", time.Hour, 0),
+		newScheduledPod("crowded", "node2", time.Hour, 0),
+	}
+	neighbour := newScheduledPod("neighbour", "node2", time.Hour, 0)
+	neighbour.Namespace = "other"
+
+	client := fake.NewSimpleClientset(&pods[0], &pods[1], &neighbour)
+	// The fake clientset does not filter by fields
+	client.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		selector := action.(k8stesting.ListAction).GetListRestrictions().Fields
+		list := &corev1.PodList{}
+		for _, pod := range []corev1.Pod{pods[0], pods[1], neighbour} {
+			if selector.Matches(fields.Set{"spec.nodeName": pod.Spec.NodeName}) {
+				list.Items = append(list.Items, pod)
+			}
+		}
+		return true, list, nil
+	})
+
+	v := newVictimBase()
+	v.podSelection = config.PodSelectionMostCrowdedNodeLabelValue
+	selected, err := v.SelectPods(client, pods, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"crowded"}, podNames(selected))
+}
+
+func TestConfigurePodSelection(t *testing.T) {
+	v := newVictimBase()
+	assert.Equal(t, config.PodSelectionRandomLabelValue, v.PodSelection())
+
+	pod := newPod("app", corev1.PodRunning)
+	pod.Labels[config.PodSelectionLabelKey] = config.PodSelectionOldestLabelValue
+	assert.NoError(t, v.ConfigurePodSelection(&pod, nil))
+	assert.Equal(t, config.PodSelectionOldestLabelValue, v.PodSelection())
+
+	pod.Labels[config.PodSelectionLabelKey] = "youngest"
+	err := v.ConfigurePodSelection(&pod, nil)
+	assert.EqualError(t, err, "Invalid value for label "+config.PodSelectionLabelKey+": youngest")
+}
@@ -0,0 +1,169 @@
+package victims
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"kube-monkey/internal/pkg/config"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+
+	kube "k8s.io/client-go/kubernetes"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// IsolationLabel returns the label removed from a pod of the victim by a
+// readiness isolation, or "" if the victim does not define one
+func (v *VictimBase) IsolationLabel() string {
+	return v.isolationLabel
+}
+
+// IsolationCleanup returns what happens to a pod isolated by a readiness
+// isolation after the attack, config.IsolationCleanupDelete or
+// config.IsolationCleanupRestore
+func (v *VictimBase) IsolationCleanup() string {
+	if v.isolationCleanup == "" {
+		return config.IsolationCleanupDelete
+	}
+	return v.isolationCleanup
+}
+
+// ConfigureReadinessIsolation sets the label removed by a readiness isolation
+// and what happens to the pod afterwards from the config.IsolationLabelLabelKey
+// and config.IsolationCleanupLabelKey settings of the workload, or the
+// defaults of its enrolled namespace ns
+func (v *VictimBase) ConfigureReadinessIsolation(obj metav1.Object, ns *corev1.Namespace) error {
+	if value, ok := SettingOrDefault(obj, ns, config.IsolationLabelLabelKey); ok {
+		if value == "" {
+			return fmt.Errorf("Invalid value for label %s: %s", config.IsolationLabelLabelKey, value)
+		}
+		v.isolationLabel = value
+	}
+
+	if value, ok := SettingOrDefault(obj, ns, config.IsolationCleanupLabelKey); ok {
+		if value != config.IsolationCleanupDelete && value != config.IsolationCleanupRestore {
+			return fmt.Errorf("Invalid value for label %s: %s", config.IsolationCleanupLabelKey, value)
+		}
+		v.isolationCleanup = value
+	}
+	return nil
+}
+
+// IsolatePodReadiness removes the isolation label from a running pod of the
+// victim for its attack duration, so that the pod drops out of the endpoints
+// of its Services and is orphaned by its ReplicaSet while it keeps running
+// Afterwards the pod gets its label back or is deleted, depending on the
+// isolation cleanup of the victim. The removed label is recorded in the
+// config.IsolatedLabelAnnotationKey annotation of the pod, so that
+// ReconcileIsolatedPods deletes the pod if kube-monkey restarts during the attack
+func (v *VictimBase) IsolatePodReadiness(clientset kube.Interface) (string, error) {
+	label := v.IsolationLabel()
+	if label == "" {
+		return "", fmt.Errorf("%s %s does not have %s label or annotation", v.kind, v.name, config.IsolationLabelLabelKey)
+	}
+
+	pods, err := v.RunningPods(clientset)
+	if err != nil {
+		return "", err
+	}
+
+	var labeled []corev1.Pod
+	for _, pod := range pods {
+		if _, ok := pod.Labels[label]; ok {
+			labeled = append(labeled, pod)
+		}
+	}
+	if len(labeled) == 0 {
+		return "", fmt.Errorf("%s %s has no running pods with label %s at the moment", v.kind, v.name, label)
+	}
+
+	targets, err := v.SelectPods(clientset, labeled, 1)
+	if err != nil {
+		return "", err
+	}
+	pod := targets[0]
+	value := pod.Labels[label]
+
+	duration, cleanup := v.AttackDuration(), v.IsolationCleanup()
+	if config.DryRun() {
+		glog.Infof("[DryRun Mode] Removed label %s from pod %s for %s/%s for %s", label, pod.Name, v.namespace, v.name, duration)
+		return isolationOutcome(label, pod.Name, duration, cleanup), nil
+	}
+
+	glog.V(6).Infof("Removing label %s from pod %s for %s %s/%s for %s", label, pod.Name, v.kind, v.namespace, v.name, duration)
+	removal := isolatedLabelPatch(label, nil, label+"="+value)
+	if _, err := clientset.CoreV1().Pods(v.namespace).Patch(context.TODO(), pod.Name, types.MergePatchType, removal, metav1.PatchOptions{}); err != nil {
+		return "", errors.Wrapf(err, "Failed to remove label %s from pod %s", label, pod.Name)
+	}
+
+	time.Sleep(duration)
+
+	if cleanup == config.IsolationCleanupRestore {
+		restore := isolatedLabelPatch(label, value, nil)
+		_, err = clientset.CoreV1().Pods(v.namespace).Patch(context.TODO(), pod.Name, types.MergePatchType, restore, metav1.PatchOptions{})
+	} else {
+		err = v.DeletePod(clientset, pod.Name)
+	}
+	if apierrors.IsNotFound(err) {
+		return fmt.Sprintf("Removed label %s from pod %s for %s, after which the pod was already gone", label, pod.Name, duration), nil
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to %s pod %s after removing label %s", cleanup, pod.Name, label)
+	}
+
+	return isolationOutcome(label, pod.Name, duration, cleanup), nil
+}
+
+// ReconcileIsolatedPods deletes the pods left without their isolation label
+// by readiness isolations that were interrupted by a restart of kube-monkey
+// The pods were orphaned by their ReplicaSets, which already replaced them
+func ReconcileIsolatedPods(clientset kube.Interface) error {
+	pods, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, pod := range pods.Items {
+		if _, ok := pod.Annotations[config.IsolatedLabelAnnotationKey]; !ok {
+			continue
+		}
+
+		glog.V(3).Infof("Deleting pod %s/%s left isolated by an interrupted readiness isolation", pod.Namespace, pod.Name)
+		err := clientset.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), pod.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func isolationOutcome(label, pod string, duration time.Duration, cleanup string) string {
+	then := "deleted the pod"
+	if cleanup == config.IsolationCleanupRestore {
+		then = "restored the label"
+	}
+	return fmt.Sprintf("Removed label %s from pod %s for %s, then %s", label, pod, duration, then)
+}
+
+// Creates a merge patch setting the label of a pod, or removing it if value
+// is nil, along with the config.IsolatedLabelAnnotationKey annotation
+func isolatedLabelPatch(label string, value interface{}, isolated interface{}) []byte {
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{
+				label: value,
+			},
+			"annotations": map[string]interface{}{
+				config.IsolatedLabelAnnotationKey: isolated,
+			},
+		},
+	})
+	return patch
+}
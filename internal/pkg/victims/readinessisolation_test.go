@@ -0,0 +1,111 @@
+package victims
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"kube-monkey/internal/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newIsolationVictim(cleanup string) *VictimBase {
+	v := newVictimBase()
+	duration := time.Millisecond
+	v.attackDuration = &duration
+	v.isolationLabel = "app"
+	v.isolationCleanup = cleanup
+	return v
+}
+
+// Records the patches applied to pods
+func patchRecorder(patches *[]string) k8stesting.ReactionFunc {
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		*patches = append(*patches, string(action.(k8stesting.PatchAction).GetPatch()))
+		return false, nil, nil
+	}
+}
+
+func TestIsolatePodReadinessRestore(t *testing.T) {
+	v := newIsolationVictim(config.IsolationCleanupRestore)
+	pod := newPod("app", corev1.PodRunning)
+	pod.Labels["app"] = "foo"
+
+	var patches []string
+	client := fake.NewSimpleClientset(&pod)
+	client.PrependReactor("patch", "pods", patchRecorder(&patches))
+
+	outcome, err := v.IsolatePodReadiness(client)
+	assert.NoError(t, err)
+	assert.Equal(t, "Removed label app from pod app for 1ms, then restored the label", outcome)
+	assert.Equal(t, []string{
+		`{"metadata":{"annotations":{"` + config.IsolatedLabelAnnotationKey + `":"app=foo"},"labels":{"app":null}}}`,
+		`{"metadata":{"annotations":{"` + config.IsolatedLabelAnnotationKey + `":null},"labels":{"app":"foo"}}}`,
+	}, patches)
+
+	restored, _ := client.CoreV1().Pods(NAMESPACE).Get(context.TODO(), "app", metav1.GetOptions{})
+	assert.Equal(t, "foo", restored.Labels["app"])
+	assert.NotContains(t, restored.Annotations, config.IsolatedLabelAnnotationKey)
+}
+
+func TestIsolatePodReadinessDelete(t *testing.T) {
+	v := newIsolationVictim(config.IsolationCleanupDelete)
+	pod := newPod("app", corev1.PodRunning)
+	pod.Labels["app"] = "foo"
+	client := fake.NewSimpleClientset(&pod)
+
+	outcome, err := v.IsolatePodReadiness(client)
+	assert.NoError(t, err)
+	assert.Equal(t, "Removed label app from pod app for 1ms, then deleted the pod", outcome)
+	assert.Empty(t, getPodList(client).Items)
+}
+
+func TestIsolatePodReadinessWithoutLabel(t *testing.T) {
+	v := newIsolationVictim(config.IsolationCleanupDelete)
+	pod := newPod("app", corev1.PodRunning)
+	client := fake.NewSimpleClientset(&pod)
+
+	_, err := v.IsolatePodReadiness(client)
+	assert.EqualError(t, err, KIND+" "+NAME+" has no running pods with label app at the moment")
+
+	v.isolationLabel = ""
+	_, err = v.IsolatePodReadiness(client)
+	assert.EqualError(t, err, KIND+" "+NAME+" does not have "+config.IsolationLabelLabelKey+" label or annotation")
+}
+
+func TestReconcileIsolatedPods(t *testing.T) {
+	isolated := newPod("isolated", corev1.PodRunning)
+	isolated.Annotations = map[string]string{config.IsolatedLabelAnnotationKey: "app=foo"}
+	other := newPod("other", corev1.PodRunning)
+	client := fake.NewSimpleClientset(&isolated, &other)
+
+	assert.NoError(t, ReconcileIsolatedPods(client))
+
+	pods := getPodList(client).Items
+	if assert.Len(t, pods, 1) {
+		assert.Equal(t, "other", pods[0].Name)
+	}
+}
+
+func TestConfigureReadinessIsolation(t *testing.T) {
+	v := newVictimBase()
+	assert.Equal(t, "", v.IsolationLabel())
+	assert.Equal(t, config.IsolationCleanupDelete, v.IsolationCleanup())
+
+	pod := newPod("app", corev1.PodRunning)
+	pod.Labels[config.IsolationLabelLabelKey] = "app"
+	pod.Labels[config.IsolationCleanupLabelKey] = config.IsolationCleanupRestore
+	assert.NoError(t, v.ConfigureReadinessIsolation(&pod, nil))
+	assert.Equal(t, "app", v.IsolationLabel())
+	assert.Equal(t, config.IsolationCleanupRestore, v.IsolationCleanup())
+
+	pod.Labels[config.IsolationCleanupLabelKey] = "keep"
+	err := v.ConfigureReadinessIsolation(&pod, nil)
+	assert.EqualError(t, err, "Invalid value for label "+config.IsolationCleanupLabelKey+": keep")
+}
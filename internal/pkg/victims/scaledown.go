@@ -0,0 +1,129 @@
+package victims
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"kube-monkey/internal/pkg/config"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ScaleClient gives access to the scale subresource of a kind of workload,
+// such as the Deployments of a namespace
+type ScaleClient interface {
+	GetScale(ctx context.Context, name string, options metav1.GetOptions) (*autoscalingv1.Scale, error)
+	UpdateScale(ctx context.Context, name string, scale *autoscalingv1.Scale, opts metav1.UpdateOptions) (*autoscalingv1.Scale, error)
+}
+
+// AnnotationPatcher applies a JSON merge patch to the named workload
+type AnnotationPatcher func(ctx context.Context, name string, patch []byte) error
+
+// ScaleDownWorkload scales the workload of the victim down by replicas for
+// its attack duration, and then restores its original replica count
+// The original count is recorded in the config.OriginalReplicasAnnotationKey
+// annotation of the workload, whose current annotations are given, so that
+// RestoreReplicas can restore it if kube-monkey restarts during the attack
+func (v *VictimBase) ScaleDownWorkload(scales ScaleClient, patch AnnotationPatcher, annotations map[string]string, replicas int) (string, error) {
+	if _, ok := annotations[config.OriginalReplicasAnnotationKey]; ok {
+		return "", fmt.Errorf("%s %s is already scaled down", v.kind, v.name)
+	}
+	if replicas <= 0 {
+		return "", fmt.Errorf("cannot scale %s %s down by %d replicas", v.kind, v.name, replicas)
+	}
+
+	scale, err := scales.GetScale(context.TODO(), v.name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	original := scale.Spec.Replicas
+	if original == 0 {
+		return "", fmt.Errorf("%s %s has no replicas at the moment", v.kind, v.name)
+	}
+	target := original - int32(replicas)
+	if target < 0 {
+		glog.Warningf("%s %s has only %d replicas, but a scale down by %d requested", v.kind, v.name, original, replicas)
+		target = 0
+	}
+
+	duration := v.AttackDuration()
+	outcome := fmt.Sprintf("Scaled down from %d to %d replicas for %s", original, target, duration)
+	if config.DryRun() {
+		glog.Infof("[DryRun Mode] Scaled down %s/%s from %d to %d replicas for %s", v.namespace, v.name, original, target, duration)
+		return outcome, nil
+	}
+
+	// Record the original count before touching the scale
+	if err := patch(context.TODO(), v.name, originalReplicasPatch(strconv.Itoa(int(original)))); err != nil {
+		return "", errors.Wrapf(err, "Failed to record original replicas of %s %s", v.kind, v.name)
+	}
+
+	glog.V(6).Infof("Scaling down %s %s/%s from %d to %d replicas for %s", v.kind, v.namespace, v.name, original, target, duration)
+	scale.Spec.Replicas = target
+	if _, err := scales.UpdateScale(context.TODO(), v.name, scale, metav1.UpdateOptions{}); err != nil {
+		// Nothing to restore, so forget the original count again
+		if patchErr := patch(context.TODO(), v.name, originalReplicasPatch(nil)); patchErr != nil {
+			glog.Errorf("Failed to remove original replicas of %s %s: %v", v.kind, v.name, patchErr)
+		}
+		return "", errors.Wrapf(err, "Failed to scale down %s %s", v.kind, v.name)
+	}
+
+	time.Sleep(duration)
+
+	if err := restoreReplicas(scales, patch, v.name, original); err != nil {
+		return "", errors.Wrapf(err, "Failed to restore %d replicas of %s %s", original, v.kind, v.name)
+	}
+	return outcome, nil
+}
+
+// RestoreReplicas restores the original replica count of a workload left
+// scaled down by an attack that was interrupted by a restart of kube-monkey
+// Returns false if the annotations of the workload record no original count
+func RestoreReplicas(scales ScaleClient, patch AnnotationPatcher, name string, annotations map[string]string) (bool, error) {
+	value, ok := annotations[config.OriginalReplicasAnnotationKey]
+	if !ok {
+		return false, nil
+	}
+
+	original, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return false, fmt.Errorf("Invalid value for annotation %s: %s", config.OriginalReplicasAnnotationKey, value)
+	}
+	return true, restoreReplicas(scales, patch, name, int32(original))
+}
+
+// Scales the workload back to its original count, and removes the record of it
+func restoreReplicas(scales ScaleClient, patch AnnotationPatcher, name string, original int32) error {
+	scale, err := scales.GetScale(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	scale.Spec.Replicas = original
+	if _, err := scales.UpdateScale(context.TODO(), name, scale, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+
+	return patch(context.TODO(), name, originalReplicasPatch(nil))
+}
+
+// Creates a merge patch setting the config.OriginalReplicasAnnotationKey
+// annotation, or removing it if original is nil
+func originalReplicasPatch(original interface{}) []byte {
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				config.OriginalReplicasAnnotationKey: original,
+			},
+		},
+	})
+	return patch
+}
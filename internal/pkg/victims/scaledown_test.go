@@ -0,0 +1,109 @@
+package victims
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"kube-monkey/internal/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Records every replica count the scale subresource is updated to
+type fakeScaleClient struct {
+	replicas int32
+	updates  []int32
+}
+
+func (f *fakeScaleClient) GetScale(ctx context.Context, name string, options metav1.GetOptions) (*autoscalingv1.Scale, error) {
+	return &autoscalingv1.Scale{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       autoscalingv1.ScaleSpec{Replicas: f.replicas},
+	}, nil
+}
+
+func (f *fakeScaleClient) UpdateScale(ctx context.Context, name string, scale *autoscalingv1.Scale, opts metav1.UpdateOptions) (*autoscalingv1.Scale, error) {
+	f.replicas = scale.Spec.Replicas
+	f.updates = append(f.updates, scale.Spec.Replicas)
+	return scale, nil
+}
+
+// Records the values the original replicas annotation is patched to
+func recordingPatcher(originals *[]interface{}) AnnotationPatcher {
+	return func(ctx context.Context, name string, patch []byte) error {
+		var parsed struct {
+			Metadata struct {
+				Annotations map[string]interface{} `json:"annotations"`
+			} `json:"metadata"`
+		}
+		if err := json.Unmarshal(patch, &parsed); err != nil {
+			return err
+		}
+		*originals = append(*originals, parsed.Metadata.Annotations[config.OriginalReplicasAnnotationKey])
+		return nil
+	}
+}
+
+func TestScaleDownWorkload(t *testing.T) {
+	v := newVictimBase()
+	duration := time.Millisecond
+	v.attackDuration = &duration
+
+	scales := &fakeScaleClient{replicas: 3}
+	var originals []interface{}
+	outcome, err := v.ScaleDownWorkload(scales, recordingPatcher(&originals), nil, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Scaled down from 3 to 1 replicas for 1ms", outcome)
+	assert.Equal(t, []int32{1, 3}, scales.updates)
+	assert.Equal(t, []interface{}{"3", nil}, originals, "Expected the original replicas to be recorded and then removed")
+}
+
+func TestScaleDownWorkloadBelowZero(t *testing.T) {
+	v := newVictimBase()
+	duration := time.Millisecond
+	v.attackDuration = &duration
+
+	scales := &fakeScaleClient{replicas: 1}
+	var originals []interface{}
+	outcome, err := v.ScaleDownWorkload(scales, recordingPatcher(&originals), nil, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Scaled down from 1 to 0 replicas for 1ms", outcome)
+	assert.Equal(t, []int32{0, 1}, scales.updates)
+}
+
+func TestScaleDownWorkloadAlreadyScaledDown(t *testing.T) {
+	v := newVictimBase()
+	scales := &fakeScaleClient{replicas: 1}
+	var originals []interface{}
+	annotations := map[string]string{config.OriginalReplicasAnnotationKey: "3"}
+
+	_, err := v.ScaleDownWorkload(scales, recordingPatcher(&originals), annotations, 1)
+	assert.EqualError(t, err, KIND+" "+NAME+" is already scaled down")
+	assert.Empty(t, scales.updates)
+	assert.Empty(t, originals)
+}
+
+func TestRestoreReplicas(t *testing.T) {
+	scales := &fakeScaleClient{replicas: 1}
+	var originals []interface{}
+
+	restored, err := RestoreReplicas(scales, recordingPatcher(&originals), "app", map[string]string{})
+	assert.NoError(t, err)
+	assert.False(t, restored)
+	assert.Empty(t, scales.updates)
+
+	restored, err = RestoreReplicas(scales, recordingPatcher(&originals), "app", map[string]string{config.OriginalReplicasAnnotationKey: "3"})
+	assert.NoError(t, err)
+	assert.True(t, restored)
+	assert.Equal(t, []int32{3}, scales.updates)
+	assert.Equal(t, []interface{}{nil}, originals)
+
+	_, err = RestoreReplicas(scales, recordingPatcher(&originals), "app", map[string]string{config.OriginalReplicasAnnotationKey: "three"})
+	assert.EqualError(t, err, "Invalid value for annotation "+config.OriginalReplicasAnnotationKey+": three")
+}
@@ -0,0 +1,33 @@
+package victims
+
+import (
+	"fmt"
+	"strings"
+
+	kube "k8s.io/client-go/kubernetes"
+)
+
+// Scenario is implemented by victims standing for an outage that spans
+// several workloads, such as all enrolled pods on a node. Running the
+// scenario picks and terminates the pods, and reports the affected workloads
+type Scenario interface {
+	Run(kube.Interface) (outcome string, affected []AffectedWorkload, err error)
+}
+
+// AffectedWorkload is a workload whose pods were terminated by a Scenario
+type AffectedWorkload struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Pods      []string
+
+	// Running pods of the workload before the outage, or 0 if unknown
+	Running int
+}
+
+func (a AffectedWorkload) String() string {
+	if a.Running == 0 {
+		return fmt.Sprintf("%s %s/%s lost %d pods (%s)", a.Kind, a.Namespace, a.Name, len(a.Pods), strings.Join(a.Pods, ", "))
+	}
+	return fmt.Sprintf("%s %s/%s lost %d of %d pods (%s)", a.Kind, a.Namespace, a.Name, len(a.Pods), a.Running, strings.Join(a.Pods, ", "))
+}
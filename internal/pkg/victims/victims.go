@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"strconv"
 	"time"
 
 	"kube-monkey/internal/pkg/config"
@@ -15,9 +16,13 @@ import (
 	kube "k8s.io/client-go/kubernetes"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
@@ -33,7 +38,8 @@ type VictimBaseTemplate interface {
 	Name() string
 	Namespace() string
 	Identifier() string
-	Mtbf() int
+	Mtbf() float64
+	KillWindow() *KillWindow
 
 	VictimAPICalls
 }
@@ -52,8 +58,8 @@ type VictimAPICalls interface {
 	DeletePod(kube.Interface, string) error
 	DeleteRandomPod(kube.Interface) error // Deprecated, but faster than DeleteRandomPods for single pod termination
 	DeleteRandomPods(kube.Interface, int) error
-	IsBlacklisted() bool
-	IsWhitelisted() bool
+	IsBlacklisted(kube.Interface) bool
+	IsWhitelisted(kube.Interface) bool
 }
 
 type VictimKillNumberGenerator interface {
@@ -62,17 +68,121 @@ type VictimKillNumberGenerator interface {
 	KillNumberForFixedPercentage(kube.Interface, int) (int, error)
 }
 
+// VictimCompletionWatcher is implemented by victims that run to completion,
+// such as Jobs, to report how they finished after a termination
+type VictimCompletionWatcher interface {
+	WaitForCompletion(kube.Interface, time.Duration) (string, error)
+}
+
+// VictimHealthReporter is implemented by victims whose status reports their
+// ready and desired replicas, such as Deployments, to skip terminations
+// of apps that are already degraded
+type VictimHealthReporter interface {
+	Replicas(kube.Interface) (ready int32, desired int32, err error)
+	MinHealthyReplicas() (*intstr.IntOrString, error)
+}
+
+// VictimNetworkIsolator is implemented by victims whose pods can be cut off
+// from the network for their attack duration, see config.KillNetworkIsolationLabelValue
+type VictimNetworkIsolator interface {
+	IsolatePods(kube.Interface) (string, error)
+}
+
+// VictimScaler is implemented by victims that can be scaled down by a number
+// of replicas for their attack duration, see config.KillScaleDownLabelValue
+type VictimScaler interface {
+	ScaleDown(kube.Interface, int) (string, error)
+}
+
+// VictimReadinessIsolator is implemented by victims whose pods can be taken
+// out of their Services for their attack duration while they keep running,
+// see config.KillReadinessIsolationLabelValue
+type VictimReadinessIsolator interface {
+	IsolatePodReadiness(kube.Interface) (string, error)
+}
+
+// VictimZoneOutageLimiter is implemented by victims that limit the share of
+// their running pods terminated by a zone outage
+type VictimZoneOutageLimiter interface {
+	ZoneOutageMaxPercent() int
+}
+
+// VictimContainerKiller is implemented by victims that can signal a container
+// of their pods instead of deleting the pods, see config.KillTargetLabelKey
+type VictimContainerKiller interface {
+	KillTarget() string
+	KillContainers(kube.Interface, PodExecutor, int) (string, error)
+}
+
+// NodeVictim is implemented by victims standing for a node opted in to chaos,
+// rather than a workload. Draining the node cordons it and evicts the pods
+// of all workloads on it, then uncordons it after its attack duration
+type NodeVictim interface {
+	Victim
+	Drain(kube.Interface) (outcome string, affected []AffectedWorkload, err error)
+}
+
+// VictimTainter is implemented by node victims that can be tainted with a
+// NoExecute taint for their attack duration, evicting the pods that do not
+// tolerate it
+type VictimTainter interface {
+	Taint(kube.Interface) (outcome string, evicted []AffectedWorkload, err error)
+}
+
+// PodSelectorFunc resolves the selector for the pods of a victim each time
+// they are listed, for victims whose pods change over time
+type PodSelectorFunc func(kube.Interface) (labels.Selector, error)
+
 type VictimBase struct {
-	kind       string
-	name       string
-	namespace  string
-	identifier string
-	mtbf       int
+	kind        string
+	name        string
+	namespace   string
+	identifier  string
+	mtbf        float64
+	podSelector labels.Selector
+	podOwner    types.UID
+	killWindow  *KillWindow
+
+	// Resolves the pod selector when the pods are listed, see SetPodSelectorFunc
+	podSelectorFunc PodSelectorFunc
+
+	// UID of the single pod the victim stands for, see SetPodUID
+	podUID types.UID
+
+	// Grace period for the pods of the victim, overriding config.GracePeriodSeconds
+	gracePeriodSec    *int64
+	usePodGracePeriod bool
+
+	// Strategy to pick the pods to kill, see config.PodSelectionLabelKey
+	podSelection string
+
+	// How pods are terminated, see config.TerminationModeLabelKey
+	terminationMode string
+
+	// Ready replicas required for a termination, see config.MinHealthyReplicasLabelKey
+	minHealthyReplicas *intstr.IntOrString
+
+	// How long temporary attacks last, see config.AttackDurationLabelKey
+	attackDuration *time.Duration
+
+	// Label removed by a readiness isolation and what happens to the pod
+	// afterwards, see config.IsolationLabelLabelKey
+	isolationLabel   string
+	isolationCleanup string
+
+	// Share of the running pods a zone outage terminates at most, see
+	// config.ZoneOutageMaxPercentLabelKey
+	zoneOutageMaxPercent *int
+
+	// Container signaled instead of deleting pods and the signal, see
+	// config.KillTargetLabelKey
+	killTarget string
+	killSignal string
 
 	VictimBaseTemplate
 }
 
-func New(kind, name, namespace, identifier string, mtbf int) *VictimBase {
+func New(kind, name, namespace, identifier string, mtbf float64) *VictimBase {
 	return &VictimBase{kind: kind, name: name, namespace: namespace, identifier: identifier, mtbf: mtbf}
 }
 
@@ -92,10 +202,91 @@ func (v *VictimBase) Identifier() string {
 	return v.identifier
 }
 
-func (v *VictimBase) Mtbf() int {
+// Mtbf returns the mean time between failures of the victim, in days
+func (v *VictimBase) Mtbf() float64 {
 	return v.mtbf
 }
 
+// MinMtbf is the shortest mean time between failures a victim may set,
+// which limits a victim to 24 terminations a day
+const MinMtbf = time.Hour
+
+// ParseMtbf parses the value of the config.MtbfLabelKey setting into days
+// The value is either a number of days, which may be fractional such as
+// "0.5", or a duration such as "36h"
+func ParseMtbf(value string) (float64, error) {
+	days, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		duration, durationErr := time.ParseDuration(value)
+		if durationErr != nil {
+			return -1, fmt.Errorf("Invalid value for label %s: %s", config.MtbfLabelKey, value)
+		}
+		days = duration.Hours() / 24
+	}
+
+	if !(days > 0) || math.IsInf(days, 1) {
+		return -1, fmt.Errorf("Invalid value for label %s: %s", config.MtbfLabelKey, value)
+	}
+	if days*24 < MinMtbf.Hours() {
+		return -1, fmt.Errorf("Invalid value for label %s: %s is shorter than %s", config.MtbfLabelKey, value, MinMtbf)
+	}
+
+	return days, nil
+}
+
+// SetPodSelector makes the victim find its pods with the given selector
+// instead of the identifier label
+func (v *VictimBase) SetPodSelector(selector labels.Selector) {
+	v.podSelector = selector
+}
+
+// SetPodSelectorFunc makes the victim find its pods with the selector
+// returned by f at the time they are listed, such as the selector for the
+// pods of the active Jobs of a CronJob
+func (v *VictimBase) SetPodSelectorFunc(f PodSelectorFunc) {
+	v.podSelectorFunc = f
+}
+
+// SetPodUID makes the victim stand for the single pod with its name and the
+// given UID, such as a bare pod, rather than the pods found by a selector
+func (v *VictimBase) SetPodUID(uid types.UID) {
+	v.podUID = uid
+}
+
+// SetPodOwner restricts the pods of the victim to those controlled by
+// the owner, directly or through a ReplicaSet or ReplicationController
+func (v *VictimBase) SetPodOwner(owner types.UID) {
+	v.podOwner = owner
+}
+
+// ConfigurePodDiscovery sets up how the pods of a workload are found.
+// With config.PodDiscoverySelector, or if the workload has no identifier,
+// the workload's own selector is used and, if config.VerifyPodOwners is set,
+// only pods owned by the workload are kept. Otherwise the identifier label
+// is used
+func (v *VictimBase) ConfigurePodDiscovery(selector *metav1.LabelSelector, owner types.UID) error {
+	if !config.PodDiscoveryBySelector() && v.identifier != "" {
+		return nil
+	}
+
+	if selector == nil {
+		return fmt.Errorf("%s %s does not have a selector", v.kind, v.name)
+	}
+	podSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return errors.Wrapf(err, "Invalid selector for %s %s", v.kind, v.name)
+	}
+	if podSelector.Empty() {
+		return fmt.Errorf("%s %s has an empty selector", v.kind, v.name)
+	}
+	v.SetPodSelector(podSelector)
+
+	if config.VerifyPodOwners() {
+		v.SetPodOwner(owner)
+	}
+	return nil
+}
+
 // RunningPods returns a list of running pods for the victim
 func (v *VictimBase) RunningPods(clientset kube.Interface) (runningPods []corev1.Pod, err error) {
 	pods, err := v.Pods(clientset)
@@ -114,7 +305,7 @@ func (v *VictimBase) RunningPods(clientset kube.Interface) (runningPods []corev1
 
 // Pods returns a list of pods under the victim
 func (v *VictimBase) Pods(clientset kube.Interface) ([]corev1.Pod, error) {
-	labelSelector, err := labelFilterForPods(v.identifier)
+	labelSelector, err := v.podFilter(clientset)
 	if err != nil {
 		return nil, err
 	}
@@ -123,31 +314,205 @@ func (v *VictimBase) Pods(clientset kube.Interface) ([]corev1.Pod, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	if v.podUID != "" {
+		return v.ownPod(podlist.Items), nil
+	}
+	if v.podOwner != "" {
+		return v.ownedPods(clientset, podlist.Items)
+	}
 	return podlist.Items, nil
 }
 
+// Keeps only the pod the victim stands for. A pod recreated with the same
+// name is another pod, and is left alone
+func (v *VictimBase) ownPod(pods []corev1.Pod) []corev1.Pod {
+	for _, pod := range pods {
+		if pod.Name == v.name && pod.UID == v.podUID {
+			return []corev1.Pod{pod}
+		}
+	}
+	return nil
+}
+
+// Keeps only the pods controlled by the pod owner of the victim
+func (v *VictimBase) ownedPods(clientset kube.Interface, pods []corev1.Pod) ([]corev1.Pod, error) {
+	// Caches whether each controller of the pods belongs to the owner
+	owned := map[types.UID]bool{v.podOwner: true}
+
+	var ownedPods []corev1.Pod
+	for _, pod := range pods {
+		controller := metav1.GetControllerOf(&pod)
+		if controller == nil {
+			continue
+		}
+
+		isOwned, checked := owned[controller.UID]
+		if !checked {
+			var err error
+			isOwned, err = v.isControlledByOwner(clientset, controller)
+			if err != nil {
+				return nil, err
+			}
+			owned[controller.UID] = isOwned
+		}
+
+		if isOwned {
+			ownedPods = append(ownedPods, pod)
+		}
+	}
+	return ownedPods, nil
+}
+
+// Checks if the intermediate controller of a pod, such as the ReplicaSet
+// of a Deployment, is controlled by the pod owner of the victim
+func (v *VictimBase) isControlledByOwner(clientset kube.Interface, ref *metav1.OwnerReference) (bool, error) {
+	var controller metav1.Object
+	var err error
+
+	switch ref.Kind {
+	case "ReplicaSet":
+		controller, err = clientset.AppsV1().ReplicaSets(v.namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+	case "ReplicationController":
+		controller, err = clientset.CoreV1().ReplicationControllers(v.namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+	default:
+		return false, nil
+	}
+
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	owner := metav1.GetControllerOf(controller)
+	return owner != nil && owner.UID == v.podOwner, nil
+}
+
 // DeletePod removes specified pod for victim
+// The pod is evicted instead if the victim uses config.TerminationModeEvict
 func (v *VictimBase) DeletePod(clientset kube.Interface, podName string) error {
 	if config.DryRun() {
 		glog.Infof("[DryRun Mode] Terminated pod %s for %s/%s", podName, v.namespace, v.name)
 		return nil
 	}
 
+	if v.TerminationMode() == config.TerminationModeEvict {
+		return v.EvictPod(clientset, podName)
+	}
+
 	deleteOpts := v.GetDeleteOptsForPod()
 	return clientset.CoreV1().Pods(v.namespace).Delete(context.TODO(), podName, *deleteOpts)
 }
 
 // Creates the DeleteOptions object
-// Grace period is derived from config
+// Grace period is derived from the setting of the victim if it has one,
+// and from config otherwise
 func (v *VictimBase) GetDeleteOptsForPod() *metav1.DeleteOptions {
+	if v.usePodGracePeriod {
+		// Without a grace period, the pod's own terminationGracePeriodSeconds applies
+		return &metav1.DeleteOptions{}
+	}
+
 	gracePeriodSec := config.GracePeriodSeconds()
+	if v.gracePeriodSec != nil {
+		gracePeriodSec = v.gracePeriodSec
+	}
 
 	return &metav1.DeleteOptions{
 		GracePeriodSeconds: gracePeriodSec,
 	}
 }
 
-// DeleteRandomPods removes specified number of random pods for the victim
+// ConfigureSettings applies the optional settings of a workload to the victim,
+// falling back to the defaults of its enrolled namespace ns
+func (v *VictimBase) ConfigureSettings(obj metav1.Object, ns *corev1.Namespace) error {
+	if err := v.ConfigureKillWindow(obj, ns); err != nil {
+		return err
+	}
+	if err := v.ConfigureGracePeriod(obj, ns); err != nil {
+		return err
+	}
+	if err := v.ConfigurePodSelection(obj, ns); err != nil {
+		return err
+	}
+	if err := v.ConfigureTerminationMode(obj, ns); err != nil {
+		return err
+	}
+	if err := v.ConfigureMinHealthyReplicas(obj, ns); err != nil {
+		return err
+	}
+	if err := v.ConfigureAttackDuration(obj, ns); err != nil {
+		return err
+	}
+	if err := v.ConfigureReadinessIsolation(obj, ns); err != nil {
+		return err
+	}
+	if err := v.ConfigureZoneOutageMaxPercent(obj, ns); err != nil {
+		return err
+	}
+	return v.ConfigureKillTarget(obj, ns)
+}
+
+// MinHealthyReplicas returns the ready replicas the victim needs for a
+// termination to go ahead, or nil if there is no minimum
+func (v *VictimBase) MinHealthyReplicas() (*intstr.IntOrString, error) {
+	if v.minHealthyReplicas != nil {
+		return v.minHealthyReplicas, nil
+	}
+	return config.MinHealthyReplicas()
+}
+
+// ConfigureMinHealthyReplicas sets the ready replicas the victim needs for a
+// termination from the config.MinHealthyReplicasLabelKey setting of the
+// workload, or the default of its enrolled namespace ns
+func (v *VictimBase) ConfigureMinHealthyReplicas(obj metav1.Object, ns *corev1.Namespace) error {
+	value, ok := SettingOrDefault(obj, ns, config.MinHealthyReplicasLabelKey)
+	if !ok {
+		return nil
+	}
+
+	minHealthy, err := config.ParseMinHealthyReplicas(value)
+	if err != nil {
+		return fmt.Errorf("Invalid value for label %s: %s", config.MinHealthyReplicasLabelKey, value)
+	}
+	v.minHealthyReplicas = minHealthy
+	return nil
+}
+
+// ConfigureGracePeriod sets the grace period for the pods of the victim from
+// the config.GracePeriodLabelKey setting of the workload, or the default of
+// its enrolled namespace ns. The value is a number of seconds, where 0 kills
+// the pods immediately, or config.GracePeriodPodLabelValue to use the
+// terminationGracePeriodSeconds of each pod
+func (v *VictimBase) ConfigureGracePeriod(obj metav1.Object, ns *corev1.Namespace) error {
+	value, ok := SettingOrDefault(obj, ns, config.GracePeriodLabelKey)
+	if !ok {
+		return nil
+	}
+
+	if value == config.GracePeriodPodLabelValue {
+		v.usePodGracePeriod = true
+		return nil
+	}
+
+	gracePeriodSec, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || gracePeriodSec < 0 {
+		return fmt.Errorf("Invalid value for label %s: %s", config.GracePeriodLabelKey, value)
+	}
+	v.gracePeriodSec = &gracePeriodSec
+	return nil
+}
+
+// UsePodGracePeriod makes the victim terminate its pods with their own
+// terminationGracePeriodSeconds, as config.GracePeriodPodLabelValue does
+func (v *VictimBase) UsePodGracePeriod() {
+	v.usePodGracePeriod = true
+}
+
+// DeleteRandomPods removes specified number of pods for the victim
+// The pods are picked without replacement, following the strategy of the victim
 func (v *VictimBase) DeleteRandomPods(clientset kube.Interface, killNum int) error {
 	// Pick a target pod to delete
 	pods, err := v.RunningPods(clientset)
@@ -174,11 +539,13 @@ func (v *VictimBase) DeleteRandomPods(clientset kube.Interface, killNum int) err
 		return fmt.Errorf("unexpected behavior for terminating %s %s", v.kind, v.name)
 	}
 
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	targets, err := v.SelectPods(clientset, pods, killNum)
+	if err != nil {
+		return err
+	}
 
-	for i := 0; i < killNum; i++ {
-		victimIndex := r.Intn(numPods)
-		targetPod := pods[victimIndex].Name
+	for _, target := range targets {
+		targetPod := target.Name
 
 		glog.V(6).Infof("Terminating pod %s for %s %s/%s\n", targetPod, v.kind, v.namespace, v.name)
 
@@ -212,21 +579,84 @@ func (v *VictimBase) DeleteRandomPod(clientset kube.Interface) error {
 }
 
 // IsBlacklisted checks if this victim is blacklisted
-func (v *VictimBase) IsBlacklisted() bool {
-	if config.BlacklistEnabled() {
-		blacklist := config.BlacklistedNamespaces()
-		return blacklist.Has(v.namespace)
+// A namespace that cannot be checked is treated as blacklisted
+func (v *VictimBase) IsBlacklisted(clientset kube.Interface) bool {
+	blacklisted, err := v.checkNamespace(clientset, IsBlacklistedNamespace)
+	if err != nil {
+		glog.Errorf("Failed to check blacklist for namespace %s, treating it as blacklisted: %v", v.namespace, err)
+		return true
 	}
-	return false
+	return blacklisted
 }
 
 // IsWhitelisted checks if this victim is whitelisted
-func (v *VictimBase) IsWhitelisted() bool {
-	if config.WhitelistEnabled() {
-		whitelist := config.WhitelistedNamespaces()
-		return whitelist.Has(v.namespace)
+// A namespace that cannot be checked is treated as not whitelisted
+func (v *VictimBase) IsWhitelisted(clientset kube.Interface) bool {
+	whitelisted, err := v.checkNamespace(clientset, IsWhitelistedNamespace)
+	if err != nil {
+		glog.Errorf("Failed to check whitelist for namespace %s, treating it as not whitelisted: %v", v.namespace, err)
+		return false
 	}
-	return true
+	return whitelisted
+}
+
+// Checks the namespace of this victim. The namespace is only fetched
+// if a namespace selector is configured and needs its labels
+func (v *VictimBase) checkNamespace(clientset kube.Interface, check func(*corev1.Namespace) (bool, error)) (bool, error) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: v.namespace}}
+
+	// Invalid selectors are reported by the check itself
+	whitelistSelector, _ := config.WhitelistedNamespaceSelector()
+	blacklistSelector, _ := config.BlacklistedNamespaceSelector()
+	if whitelistSelector != nil || blacklistSelector != nil {
+		var err error
+		ns, err = clientset.CoreV1().Namespaces().Get(context.TODO(), v.namespace, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return check(ns)
+}
+
+// Create the filter for pods of this victim, using the name of its pod if it
+// stands for a single pod, the pod selector if one was set and the
+// identifier label otherwise
+func (v *VictimBase) podFilter(clientset kube.Interface) (*metav1.ListOptions, error) {
+	if v.podUID != "" {
+		return &metav1.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector("metadata.name", v.name).String(),
+		}, nil
+	}
+
+	selector, err := v.resolvePodSelector(clientset)
+	if err != nil {
+		return nil, err
+	}
+	if selector != nil {
+		return &metav1.ListOptions{
+			LabelSelector: selector.String(),
+		}, nil
+	}
+	return labelFilterForPods(v.identifier)
+}
+
+// Returns the pod selector of this victim, resolving it first if it has a
+// PodSelectorFunc, or nil if the pods are found with the identifier label
+func (v *VictimBase) resolvePodSelector(clientset kube.Interface) (labels.Selector, error) {
+	if v.podSelectorFunc == nil {
+		return v.podSelector, nil
+	}
+
+	selector, err := v.podSelectorFunc(clientset)
+	if err != nil {
+		return nil, err
+	}
+	// An empty selector would match every pod in the namespace
+	if selector.Empty() {
+		return nil, fmt.Errorf("%s %s has an empty pod selector", v.kind, v.name)
+	}
+	return selector, nil
 }
 
 // Create a label filter to filter only for pods that belong to the this
@@ -247,6 +677,18 @@ func labelRequirementForPods(identifier string) (*labels.Requirement, error) {
 	return labels.NewRequirement(config.IdentLabelKey, selection.Equals, sets.NewString(identifier).UnsortedList())
 }
 
+// Setting returns the value of a kube-monkey setting, such as
+// config.MtbfLabelKey, from the annotations or labels of a workload
+// Annotations take precedence over labels, as they are not restricted in
+// length and changing them does not roll the pods of a Deployment
+func Setting(obj metav1.Object, key string) (string, bool) {
+	if value, ok := obj.GetAnnotations()[key]; ok {
+		return value, true
+	}
+	value, ok := obj.GetLabels()[key]
+	return value, ok
+}
+
 // RandomPodName picks a random pod name from a list of Pods
 func RandomPodName(pods []corev1.Pod) string {
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
@@ -9,10 +9,15 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"kube-monkey/internal/pkg/config"
+	"kube-monkey/internal/pkg/config/param"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	kube "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
 )
@@ -75,7 +80,30 @@ func TestVictimBaseTemplateGetters(t *testing.T) {
 	assert.Equal(t, "name", v.Name())
 	assert.Equal(t, NAMESPACE, v.Namespace())
 	assert.Equal(t, IDENTIFIER, v.Identifier())
-	assert.Equal(t, 1, v.Mtbf())
+	assert.Equal(t, float64(1), v.Mtbf())
+}
+
+func TestParseMtbf(t *testing.T) {
+	for value, days := range map[string]float64{
+		"2":   2,
+		"0.5": 0.5,
+		"36h": 1.5,
+		"1h":  1.0 / 24,
+	} {
+		mtbf, err := ParseMtbf(value)
+		assert.NoError(t, err)
+		assert.InDelta(t, days, mtbf, 1e-9, "Unexpected mtbf for %s", value)
+	}
+
+	for _, value := range []string{"0", "-1", "-2h", "string", "Inf", "NaN"} {
+		_, err := ParseMtbf(value)
+		assert.EqualError(t, err, "Invalid value for label "+config.MtbfLabelKey+": "+value)
+	}
+
+	for _, value := range []string{"30m", "1s", "1ns", "0.01"} {
+		_, err := ParseMtbf(value)
+		assert.EqualError(t, err, "Invalid value for label "+config.MtbfLabelKey+": "+value+" is shorter than 1h0m0s")
+	}
 }
 
 func TestRunningPods(t *testing.T) {
@@ -108,6 +136,79 @@ func TestPods(t *testing.T) {
 	assert.Lenf(t, podList, 2, "Expected 2 items in podList, got %d", len(podList))
 }
 
+func TestPodsWithSelector(t *testing.T) {
+
+	v := newVictimBase()
+	v.SetPodSelector(labels.SelectorFromSet(labels.Set{"app": "selected"}))
+	pod1 := newPod("app1", corev1.PodRunning)
+	pod1.Labels["app"] = "selected"
+	pod2 := newPod("app2", corev1.PodRunning)
+
+	client := fake.NewSimpleClientset(&pod1, &pod2)
+
+	podList, _ := v.Pods(client)
+
+	assert.Lenf(t, podList, 1, "Expected 1 item in podList, got %d", len(podList))
+	assert.Equal(t, "app1", podList[0].GetName())
+}
+
+func TestPodsOwnedByVictim(t *testing.T) {
+
+	v := newVictimBase()
+	v.SetPodSelector(labels.Everything())
+	v.SetPodOwner("deployment-uid")
+
+	isController := true
+	rs := appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rs",
+			Namespace: NAMESPACE,
+			UID:       "rs-uid",
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "Deployment", Name: NAME, UID: "deployment-uid", Controller: &isController},
+			},
+		},
+	}
+	pod1 := newPod("app1", corev1.PodRunning)
+	pod1.OwnerReferences = []metav1.OwnerReference{
+		{Kind: "ReplicaSet", Name: "rs", UID: "rs-uid", Controller: &isController},
+	}
+	pod2 := newPod("app2", corev1.PodRunning)
+	pod2.OwnerReferences = []metav1.OwnerReference{
+		{Kind: "ReplicaSet", Name: "other", UID: "other-uid", Controller: &isController},
+	}
+	pod3 := newPod("app3", corev1.PodRunning)
+
+	client := fake.NewSimpleClientset(&rs, &pod1, &pod2, &pod3)
+
+	podList, err := v.Pods(client)
+
+	assert.NoError(t, err)
+	assert.Lenf(t, podList, 1, "Expected 1 item in podList, got %d", len(podList))
+	assert.Equal(t, "app1", podList[0].GetName())
+}
+
+func TestConfigurePodDiscovery(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}}
+
+	v := newVictimBase()
+	assert.NoError(t, v.ConfigurePodDiscovery(selector, "uid"))
+	assert.Nil(t, v.podSelector, "Expected the identifier label to be used by default")
+
+	viper.Set(param.PodDiscovery, config.PodDiscoverySelector)
+	viper.Set(param.VerifyPodOwners, true)
+	defer viper.Set(param.PodDiscovery, config.PodDiscoveryIdentifier)
+	defer viper.Set(param.VerifyPodOwners, false)
+
+	v = newVictimBase()
+	assert.NoError(t, v.ConfigurePodDiscovery(selector, "uid"))
+	assert.Equal(t, "app=foo", v.podSelector.String())
+	assert.Equal(t, types.UID("uid"), v.podOwner)
+
+	assert.Error(t, v.ConfigurePodDiscovery(nil, "uid"), "Expected an error for a missing selector")
+	assert.Error(t, v.ConfigurePodDiscovery(&metav1.LabelSelector{}, "uid"), "Expected an error for an empty selector")
+}
+
 func TestDeletePod(t *testing.T) {
 
 	v := newVictimBase()
@@ -301,11 +402,13 @@ func TestIsBlacklisted(t *testing.T) {
 
 	config.SetDefaults()
 
-	b := v.IsBlacklisted()
+	client := fake.NewSimpleClientset()
+
+	b := v.IsBlacklisted(client)
 	assert.False(t, b, "%s namespace should not be blacklisted", NAMESPACE)
 
 	v = New("Pod", "name", metav1.NamespaceSystem, IDENTIFIER, 1)
-	b = v.IsBlacklisted()
+	b = v.IsBlacklisted(client)
 	assert.True(t, b, "%s namespace should be blacklisted", metav1.NamespaceSystem)
 
 }
@@ -316,10 +419,63 @@ func TestIsWhitelisted(t *testing.T) {
 
 	config.SetDefaults()
 
-	b := v.IsWhitelisted()
+	b := v.IsWhitelisted(fake.NewSimpleClientset())
 	assert.True(t, b, "%s namespace should be whitelisted", NAMESPACE)
 }
 
+func TestNamespaceSelectors(t *testing.T) {
+	v := newVictimBase()
+
+	config.SetDefaults()
+	viper.Set(param.WhitelistedNamespaceSelector, "env in (staging)")
+	viper.Set(param.BlacklistedNamespaceSelector, "tier=critical")
+	defer viper.Set(param.WhitelistedNamespaceSelector, "")
+	defer viper.Set(param.BlacklistedNamespaceSelector, "")
+
+	client := fake.NewSimpleClientset()
+	assert.True(t, v.IsBlacklisted(client), "Expected a namespace that cannot be fetched to be blacklisted")
+	assert.False(t, v.IsWhitelisted(client), "Expected a namespace that cannot be fetched not to be whitelisted")
+
+	client = fake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   NAMESPACE,
+			Labels: map[string]string{"env": "staging"},
+		},
+	})
+	assert.False(t, v.IsBlacklisted(client), "%s namespace should not be blacklisted", NAMESPACE)
+	assert.True(t, v.IsWhitelisted(client), "%s namespace should be whitelisted", NAMESPACE)
+
+	client = fake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   NAMESPACE,
+			Labels: map[string]string{"env": "production", "tier": "critical"},
+		},
+	})
+	assert.True(t, v.IsBlacklisted(client), "%s namespace should be blacklisted", NAMESPACE)
+	assert.False(t, v.IsWhitelisted(client), "%s namespace should not be whitelisted", NAMESPACE)
+}
+
+func TestSetting(t *testing.T) {
+
+	pod := newPod("app", corev1.PodRunning)
+	pod.Labels[config.MtbfLabelKey] = "1"
+	pod.Labels[config.KillTypeLabelKey] = config.KillFixedLabelValue
+	pod.Annotations = map[string]string{
+		config.KillTypeLabelKey: config.KillAllLabelValue,
+	}
+
+	value, ok := Setting(&pod, config.MtbfLabelKey)
+	assert.True(t, ok)
+	assert.Equal(t, "1", value, "Expected the label to be used without an annotation")
+
+	value, ok = Setting(&pod, config.KillTypeLabelKey)
+	assert.True(t, ok)
+	assert.Equal(t, config.KillAllLabelValue, value, "Expected the annotation to take precedence over the label")
+
+	_, ok = Setting(&pod, config.KillValueLabelKey)
+	assert.False(t, ok)
+}
+
 func TestRandomPodName(t *testing.T) {
 
 	pod1 := newPod("app1", corev1.PodRunning)
@@ -338,3 +494,34 @@ func TestGetDeleteOptsForPod(t *testing.T) {
 	assert.Equal(t, deleteOpts.GracePeriodSeconds, configuredGracePeriod)
 
 }
+
+func TestConfigureGracePeriod(t *testing.T) {
+	pod := newPod("app", corev1.PodRunning)
+
+	v := newVictimBase()
+	pod.Labels[config.GracePeriodLabelKey] = "0"
+	assert.NoError(t, v.ConfigureGracePeriod(&pod, nil))
+	assert.Equal(t, int64(0), *v.GetDeleteOptsForPod().GracePeriodSeconds, "Expected a hard kill")
+
+	v = newVictimBase()
+	pod.Labels[config.GracePeriodLabelKey] = config.GracePeriodPodLabelValue
+	assert.NoError(t, v.ConfigureGracePeriod(&pod, nil))
+	assert.Nil(t, v.GetDeleteOptsForPod().GracePeriodSeconds, "Expected the grace period of the pod spec")
+
+	v = newVictimBase()
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        NAMESPACE,
+			Annotations: map[string]string{config.GracePeriodLabelKey: "30"},
+		},
+	}
+	delete(pod.Labels, config.GracePeriodLabelKey)
+	assert.NoError(t, v.ConfigureGracePeriod(&pod, ns))
+	assert.Equal(t, int64(30), *v.GetDeleteOptsForPod().GracePeriodSeconds, "Expected the grace period default of the namespace")
+
+	for _, value := range []string{"-1", "forever"} {
+		pod.Labels[config.GracePeriodLabelKey] = value
+		err := newVictimBase().ConfigureGracePeriod(&pod, nil)
+		assert.EqualError(t, err, "Invalid value for label "+config.GracePeriodLabelKey+": "+value)
+	}
+}
@@ -0,0 +1,38 @@
+package victims
+
+import (
+	"fmt"
+	"strconv"
+
+	"kube-monkey/internal/pkg/config"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ZoneOutageMaxPercent returns the largest share of the running pods of the
+// victim, in percent, that a zone outage terminates
+func (v *VictimBase) ZoneOutageMaxPercent() int {
+	if v.zoneOutageMaxPercent == nil {
+		return config.ZoneOutageMaxPercent()
+	}
+	return *v.zoneOutageMaxPercent
+}
+
+// ConfigureZoneOutageMaxPercent sets the largest share of the running pods
+// of the victim that a zone outage terminates from the
+// config.ZoneOutageMaxPercentLabelKey setting of the workload, or the default
+// of its enrolled namespace ns
+func (v *VictimBase) ConfigureZoneOutageMaxPercent(obj metav1.Object, ns *corev1.Namespace) error {
+	value, ok := SettingOrDefault(obj, ns, config.ZoneOutageMaxPercentLabelKey)
+	if !ok {
+		return nil
+	}
+
+	percent, err := strconv.Atoi(value)
+	if err != nil || percent < 0 || percent > 100 {
+		return fmt.Errorf("Invalid value for label %s: %s", config.ZoneOutageMaxPercentLabelKey, value)
+	}
+	v.zoneOutageMaxPercent = &percent
+	return nil
+}
@@ -0,0 +1,24 @@
+package victims
+
+import (
+	"testing"
+
+	"kube-monkey/internal/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestConfigureZoneOutageMaxPercent(t *testing.T) {
+	v := newVictimBase()
+	assert.Equal(t, config.ZoneOutageMaxPercent(), v.ZoneOutageMaxPercent())
+
+	pod := newPod("app", corev1.PodRunning)
+	pod.Labels[config.ZoneOutageMaxPercentLabelKey] = "50"
+	assert.NoError(t, v.ConfigureZoneOutageMaxPercent(&pod, nil))
+	assert.Equal(t, 50, v.ZoneOutageMaxPercent())
+
+	pod.Labels[config.ZoneOutageMaxPercentLabelKey] = "150"
+	err := v.ConfigureZoneOutageMaxPercent(&pod, nil)
+	assert.EqualError(t, err, "Invalid value for label "+config.ZoneOutageMaxPercentLabelKey+": 150")
+}